@@ -5,48 +5,124 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/chromedp/cdproto/network"
 	"github.com/chromedp/cdproto/page"
 	"github.com/chromedp/chromedp"
-	"github.com/chromedp/chromedp/device"
 )
 
 // Audit coordinates the website auditing process
 type Audit struct {
 	checksStr     string
-	checks        auditChecks
+	checks        []Check
 	important     bool
 	screenshotDir string
+	concurrency   int             // number of sites audited in parallel (one tab each)
+	serialLoad    bool            // load one site at a time, so timing metrics aren't skewed
+	loadSlot      chan struct{}   // held while a page loads, when serialLoad is set
+	crawlDepth    int             // how many links deep to crawl from the entry page
+	crawlPages    int             // max pages audited per site
+	exactURLs     bool            // audit the input URLs as given, instead of each site's homepage
+	throttle      throttleProfile // network and CPU throttling applied while loading pages
+	budgets       []budget        // budgets each site is evaluated against, if any
+	runs          int             // times each page is loaded, to sample timing metrics
+	har           bool            // export requests of each site's page loads to a HAR file
+	certExpiry    int             // days before expiry certificates are warned about
+	devicesStr    string
+	devices       []deviceProfile        // devices each site is audited on
+	completed     map[string]auditResult // results from a resumed run, by target key
+	retry         retryPolicy
 }
-type auditChecks struct {
-	secure           auditCheck[bool]
-	lcp              auditCheck[float64]
-	consoleErrs      auditCheck[[]string]
-	requestErrs      auditCheck[[]string]
-	missingHeaders   auditCheck[[]string]
-	responsiveIssues auditCheck[[]string]
-	formIssues       auditCheck[[]string]
-	techStack        auditCheck[[]string]
-	screenshot       auditCheck[bool]
-}
-type auditCheck[T interface{}] struct {
-	enabled bool
-	result  T
+
+// auditOptions holds the settings an Audit is created with
+type auditOptions struct {
+	checks        string
+	important     bool
+	screenshotDir string
+	concurrency   int
+	serialLoad    bool
+	crawlDepth    int
+	crawlPages    int
+	exactURLs     bool
+	throttle      string
+	budgetsFile   string
+	runs          int
+	har           bool
+	certExpiry    int
+	devices       string
+	retries       int
+	retryBackoff  time.Duration
 }
 
 // NewAudit creates a new Audit instance
-func NewAudit(checksStr string, important bool, screenshotDir string) (*Audit, error) {
-	audit := Audit{checksStr: checksStr, important: important, screenshotDir: screenshotDir}
+func NewAudit(opts auditOptions) (*Audit, error) {
+	audit := Audit{
+		checksStr:     opts.checks,
+		important:     opts.important,
+		screenshotDir: opts.screenshotDir,
+		concurrency:   opts.concurrency,
+		serialLoad:    opts.serialLoad,
+		loadSlot:      make(chan struct{}, 1),
+		crawlDepth:    opts.crawlDepth,
+		crawlPages:    opts.crawlPages,
+		exactURLs:     opts.exactURLs,
+		devicesStr:    opts.devices,
+		runs:          opts.runs,
+		har:           opts.har,
+		certExpiry:    opts.certExpiry,
+		retry:         retryPolicy{maxAttempts: opts.retries + 1, backoff: opts.retryBackoff},
+	}
 
 	err := audit.parseAndValidateChecks()
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse audit checks: %w", err)
 	}
 
+	audit.devices, err = parseDevices(audit.devicesStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse devices: %w", err)
+	}
+
+	audit.throttle, err = parseThrottle(opts.throttle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse throttling profile: %w", err)
+	}
+
+	audit.budgets, err = loadBudgets(opts.budgetsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load budgets: %w", err)
+	}
+
+	for _, budget := range audit.budgets {
+		if budget.metric.check != "" && !audit.isEnabled(budget.metric.check) {
+			return nil, fmt.Errorf("%s budget needs the %s check enabled", budget.metric.name, budget.metric.check)
+		}
+	}
+
+	if audit.concurrency < 1 {
+		return nil, fmt.Errorf("concurrency must be at least 1")
+	}
+
+	if audit.runs < 1 {
+		return nil, fmt.Errorf("runs must be at least 1")
+	}
+
+	if audit.certExpiry < 0 {
+		return nil, fmt.Errorf("certificate expiry window can't be negative")
+	}
+
+	if opts.retries < 0 || opts.retryBackoff < 0 {
+		return nil, fmt.Errorf("retries and retry backoff can't be negative")
+	}
+
+	if audit.crawlDepth < 0 || audit.crawlPages < 1 {
+		return nil, fmt.Errorf("crawl depth can't be negative and at least 1 page must be audited")
+	}
+
 	err = audit.validateAndCreateScreenshotDir()
 	if err != nil {
 		return nil, fmt.Errorf("failed screenshot directory validation/creation: %w", err)
@@ -65,67 +141,74 @@ func (a *Audit) parseAndValidateChecks() error {
 
 	// set predefined important checks
 	if a.important {
-		a.checks = auditChecks{
-			secure:           auditCheck[bool]{enabled: true},
-			responsiveIssues: auditCheck[[]string]{enabled: true},
-			formIssues:       auditCheck[[]string]{enabled: true},
-			techStack:        auditCheck[[]string]{enabled: true},
+		for _, check := range checkRegistry {
+			if check.Important() {
+				a.checks = append(a.checks, check)
+			}
 		}
 		return nil
 	}
 
-	// if no checks specified, set all enabled
+	// if no checks specified, set all enabled, except opt-in ones
 	if a.checksStr == "" {
-		a.checks = auditChecks{
-			secure:           auditCheck[bool]{enabled: true},
-			lcp:              auditCheck[float64]{enabled: true},
-			consoleErrs:      auditCheck[[]string]{enabled: true},
-			requestErrs:      auditCheck[[]string]{enabled: true},
-			missingHeaders:   auditCheck[[]string]{enabled: true},
-			responsiveIssues: auditCheck[[]string]{enabled: true},
-			formIssues:       auditCheck[[]string]{enabled: true},
-			techStack:        auditCheck[[]string]{enabled: true},
-			screenshot:       auditCheck[bool]{enabled: true},
+		for _, check := range checkRegistry {
+			if !check.OptIn() {
+				a.checks = append(a.checks, check)
+			}
 		}
 		return nil
 	}
 
-	// all checks are disabled by default when initialising audit instance
 	// enable specified ones
-	for check := range strings.SplitSeq(a.checksStr, ",") {
-		check = strings.TrimSpace(check)
-		switch check {
-		case "security":
-			a.checks.secure.enabled = true
-		case "lcp":
-			a.checks.lcp.enabled = true
-		case "console":
-			a.checks.consoleErrs.enabled = true
-		case "request":
-			a.checks.requestErrs.enabled = true
-		case "headers":
-			a.checks.missingHeaders.enabled = true
-		case "mobile":
-			a.checks.responsiveIssues.enabled = true
-		case "form":
-			a.checks.formIssues.enabled = true
-		case "tech":
-			a.checks.techStack.enabled = true
-		case "screenshot":
-			a.checks.screenshot.enabled = true
-		default:
-			return fmt.Errorf("unknown check: %s", check)
-		}
+	checks, err := parseChecks(a.checksStr)
+	if err != nil {
+		return err
 	}
 
+	a.checks = checks
+	a.warnOptInChecks()
 	return nil
 }
 
-// validateAndCreateScreenshotDir checks whether screenshots are enabled,
-// and ensures screenshot directory exists (or if not, create it)
+// warnOptInChecks warns when opt-in checks, which slow the page down, are run
+// along with checks measuring timings on the same load
+func (a *Audit) warnOptInChecks() {
+	timed := []string{}
+	for _, check := range a.checks {
+		if check.Sampled() {
+			timed = append(timed, check.Name())
+		}
+	}
+	if len(timed) == 0 {
+		return
+	}
+
+	for _, check := range a.checks {
+		if check.OptIn() {
+			fmt.Printf(
+				"⚠️ the %s check slows the page down, so %s timings are measured on a slower load (use -runs above 1 for clean samples)\n",
+				check.Name(), strings.Join(timed, ", "),
+			)
+		}
+	}
+}
+
+// isEnabled reports whether the check with the given name will be run
+func (a *Audit) isEnabled(name string) bool {
+	for _, check := range a.checks {
+		if check.Name() == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validateAndCreateScreenshotDir checks whether screenshots (or HAR files) are
+// enabled, and ensures screenshot directory exists (or if not, create it)
 func (a *Audit) validateAndCreateScreenshotDir() error {
-	if !a.checks.screenshot.enabled {
-		return nil // not capturing screenshots
+	if !a.isEnabled(screenshotCheck.Name()) && !a.har {
+		return nil // not capturing screenshots or HAR files
 	}
 
 	// ensure directory exists, else create it
@@ -140,12 +223,70 @@ func (a *Audit) validateAndCreateScreenshotDir() error {
 // auditResult holds audit results data useful for output
 type auditResult struct {
 	website   string
-	checks    auditChecks
-	auditErrs []string
+	device    string       // name of the device the site was audited on
+	throttle  string       // name of the throttling profile pages were loaded with
+	runs      int          // times each page was loaded
+	checks    []Check      // enabled checks, in output order
+	multiPage bool         // whether multiple pages were audited per site (crawled or listed)
+	pages     []pageResult // audited pages, entry page first
+	budgets   []budgetResult
+	harFile   string // path of the exported HAR file, if exported
+	settings  auditSettings
+}
+
+// auditSettings holds the settings a result was audited with that change its
+// results, beyond its checks, throttling and runs - compared when resuming
+type auditSettings struct {
+	CrawlDepth int  `json:"crawlDepth"`
+	CrawlPages int  `json:"crawlPages"`
+	ExactURLs  bool `json:"exactURLs"`
+	Important  bool `json:"important"`
+	CertExpiry int  `json:"certExpiry"`
 }
 
-// Run executes the audit process in a headless browser, performing specified checks
-func (a *Audit) Run(ctx context.Context, websites []*Website) ([]auditResult, error) {
+// settings returns the audit's settings results are compared by when resuming
+func (a *Audit) settings() auditSettings {
+	return auditSettings{
+		CrawlDepth: a.crawlDepth,
+		CrawlPages: a.crawlPages,
+		ExactURLs:  a.exactURLs,
+		Important:  a.important,
+		CertExpiry: a.certExpiry,
+	}
+}
+
+// pageResult holds the audit results of a single page
+type pageResult struct {
+	url        string
+	attempts   int               // number of times the page was loaded
+	failure    failureCategory   // why the page couldn't be (fully) audited, if it couldn't
+	httpStatus int64             // main document response status
+	results    map[string]any    // check results, by check name
+	checkErrs  map[string]string // errors of checks that failed, by check name
+	auditErrs  []string
+	timings    map[string]timingStats // timing metrics sampled across runs, if more than one
+	requests   []networkRequest       // requests made while loading the page, for HAR export
+}
+
+// errors returns the page's audit errors, followed by errors of failed checks
+func (p pageResult) errors(checks []Check) []string {
+	errs := append([]string{}, p.auditErrs...)
+	for _, check := range checks {
+		if err, ok := p.checkErrs[check.Name()]; ok {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// Run executes the audit process in a headless browser, performing specified checks -
+// onResult (if set) is called with each result as soon as it's ready
+func (a *Audit) Run(
+	ctx context.Context,
+	websites []*Website,
+	onResult func(auditResult),
+) ([]auditResult, error) {
 	if a == nil {
 		return nil, fmt.Errorf("nil audit")
 	}
@@ -192,29 +333,296 @@ func (a *Audit) Run(ctx context.Context, websites []*Website) ([]auditResult, er
 		return nil, fmt.Errorf("failed to open browser: %w", err)
 	}
 
-	sitesNo := len(websites)
-	results := make([]auditResult, sitesNo)
+	// each site is audited once per device
+	type auditTarget struct {
+		website *Website
+		device  deviceProfile
+	}
+	targets := []auditTarget{}
+	for _, website := range websites {
+		for _, device := range a.devices {
+			targets = append(targets, auditTarget{website: website, device: device})
+		}
+	}
+
+	targetsNo := len(targets)
+	results := make([]auditResult, targetsNo)
+
+	// audit websites using a pool of workers, each working in its own tab -
+	// results are stored by index so input order is kept
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	var resultMu sync.Mutex // serialises onResult calls
+
+	for range min(a.concurrency, targetsNo) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range jobs {
+				// the browser crashed (or the run was interrupted), so remaining
+				// sites are left for a resumed run
+				if browserCtx.Err() != nil {
+					continue
+				}
+
+				target := targets[i]
+				label := target.website.domain
+				if len(a.devices) > 1 {
+					label += ", " + target.device.name
+				}
+
+				fmt.Printf("\r - auditing site %d/%d (%s)\n", i+1, targetsNo, label)
+				results[i] = a.runSingle(browserCtx, target.website, target.device)
+
+				if onResult != nil {
+					resultMu.Lock()
+					onResult(results[i])
+					resultMu.Unlock()
+				}
+
+				if a.concurrency > 1 {
+					fmt.Printf("\r - finished site %d/%d (%s)\n", i+1, targetsNo, label)
+				}
+			}
+		}()
+	}
+
+	for i, target := range targets {
+		// reuse results completed by a resumed run
+		completed, ok := a.completed[targetKey(target.website.domain, target.device.name)]
+		if ok {
+			fmt.Printf("\r - skipping site %d/%d (%s, already audited)\n", i+1, targetsNo, target.website.domain)
+			results[i] = completed
+			continue
+		}
+
+		select {
+		case jobs <- i:
+		case <-browserCtx.Done():
+		}
+	}
+	close(jobs)
+	wg.Wait()
 
-	for i, website := range websites {
-		// audit each website
-		fmt.Printf("\r - auditing site %d/%d (%s)\n", i+1, sitesNo, website.domain)
-		results[i] = a.runSingle(browserCtx, website)
+	// returning an error keeps the checkpoint, so audited sites aren't lost
+	if browserCtx.Err() != nil {
+		return nil, fmt.Errorf("browser closed before all sites were audited: %w", browserCtx.Err())
 	}
 
 	return results, nil
 }
 
-// runSingle opens the site in a headless browser and executes various checks
-// before returning an audit result
-func (a *Audit) runSingle(ctx context.Context, website *Website) auditResult {
-	result := auditResult{website: website.domain, checks: a.checks}
+// Resume sets results completed by a previous run, so their sites aren't audited
+// again - results audited with different settings, or cut short by the browser
+// crashing, are ignored
+func (a *Audit) Resume(completed []auditResult) {
+	a.completed = map[string]auditResult{}
+
+	for _, res := range completed {
+		if !sameChecks(res.checks, a.checks) || res.throttle != a.throttle.name || res.runs != a.runs || res.settings != a.settings() {
+			continue
+		}
+
+		crashed := slices.ContainsFunc(res.pages, func(page pageResult) bool {
+			return page.failure == failureBrowser
+		})
+		if crashed {
+			continue
+		}
+
+		// budgets may have changed since the previous run
+		res.budgets = evaluateBudgets(a.budgets, res)
+		a.completed[targetKey(res.website, res.device)] = res
+	}
+}
+
+// targetKey returns a key identifying a site audited on a device
+func targetKey(domain, device string) string {
+	return domain + "|" + device
+}
+
+// sameChecks reports whether both check lists hold the same checks, in order
+func sameChecks(a, b []Check) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i].Name() != b[i].Name() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// exportedHAR reports whether HAR files were exported for any of the results
+func exportedHAR(results []auditResult) bool {
+	for _, res := range results {
+		if res.harFile != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// multipleDevices reports whether results were audited on more than one device
+func multipleDevices(results []auditResult) bool {
+	for _, res := range results {
+		if res.device != results[0].device {
+			return true
+		}
+	}
+
+	return false
+}
+
+// runSingle audits the site's entry pages (its homepage, or the input URLs if
+// exact URLs are enabled) and, if crawling is enabled, the internal pages
+// discovered from them, before returning an audit result
+func (a *Audit) runSingle(ctx context.Context, website *Website, device deviceProfile) auditResult {
+	result := auditResult{
+		website:   website.domain,
+		device:    device.name,
+		throttle:  a.throttle.name,
+		runs:      a.runs,
+		checks:    a.checks,
+		multiPage: a.crawlDepth > 0 || a.exactURLs,
+		settings:  a.settings(),
+	}
+
+	// force site to load over http in order to check if it auto redirects
+	// (if security check is enabled)
+	forcedScheme := ""
+	if a.isEnabled(securityCheck.Name()) {
+		forcedScheme = "http"
+	}
+
+	entryURLs := []string{}
+	if a.exactURLs {
+		entryURLs = website.pageURLs(forcedScheme)
+	} else {
+		websiteScheme := website.scheme
+		if forcedScheme != "" {
+			websiteScheme = forcedScheme
+		}
+		entryURLs = append(entryURLs, websiteScheme+"://"+website.domain+"/")
+	}
+
+	// breadth first crawl, starting from the entry pages
+	type crawlPage struct {
+		url   string
+		depth int
+	}
+	queue := []crawlPage{}
+	seen := map[string]bool{}
+	for _, entryURL := range entryURLs {
+		key := crawlKey(entryURL)
+		if seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		queue = append(queue, crawlPage{url: entryURL})
+	}
+
+	// entry pages are always audited, even past the page limit
+	for len(queue) > 0 && (len(result.pages) < a.crawlPages || queue[0].depth == 0) {
+		next := queue[0]
+		queue = queue[1:]
+
+		if len(result.pages) > 0 {
+			fmt.Printf("\r   - auditing page %s\n", next.url)
+		}
+
+		pageRes, links := a.runPageSamples(ctx, website, device, next.url, next.depth < a.crawlDepth)
+
+		// Chrome refuses to load pages with rejected certificates, so for the tls
+		// check the certificate is read from the site directly
+		if pageRes.failure == failureTLS && a.isEnabled(tlsCheck.Name()) {
+			info, err := fetchCertificateInfo(ctx, next.url, a.certExpiry, time.Now())
+			if err != nil {
+				pageRes.checkErrs[tlsCheck.Name()] = err.Error()
+			} else {
+				pageRes.results[tlsCheck.Name()] = info
+			}
+		}
+
+		result.pages = append(result.pages, pageRes)
+
+		for _, link := range links {
+			key := crawlKey(link)
+			if seen[key] {
+				continue
+			}
+
+			seen[key] = true
+			queue = append(queue, crawlPage{url: link, depth: next.depth + 1})
+		}
+	}
+
+	result.budgets = evaluateBudgets(a.budgets, result)
+
+	if a.har {
+		name := website.domain
+		if len(a.devices) > 1 {
+			name += "_" + device.name
+		}
+
+		harFile, err := writeHAR(a.screenshotDir, name, result)
+		if err != nil {
+			fmt.Printf("⚠️ %s: %v\n", website.domain, err)
+		}
+		result.harFile = harFile
+	}
+
+	// recorded requests aren't needed past the HAR export
+	for i := range result.pages {
+		result.pages[i].requests = nil
+	}
+
+	return result
+}
+
+// runPage opens a single page in a new tab and executes the given checks, returning
+// its result along with internal links found on it (if discoverLinks is set), and
+// the error that stopped the page from being audited, if any
+func (a *Audit) runPage(
+	ctx context.Context,
+	website *Website,
+	device deviceProfile,
+	pageURL string,
+	checks []Check,
+	discoverLinks bool,
+) (pageResult, []string, error) {
+	result := pageResult{url: pageURL, results: map[string]any{}, checkErrs: map[string]string{}}
+
+	// load one site at a time if serial loading is enabled, so parallel loads
+	// don't compete for bandwidth and CPU - the slot is taken before the page's
+	// timeout starts, so waiting for it doesn't count towards it
+	releaseLoad := func() {}
+	if a.serialLoad {
+		select {
+		case a.loadSlot <- struct{}{}:
+		case <-ctx.Done():
+			err := fmt.Errorf("failed to wait for page load slot: %w", ctx.Err())
+			result.auditErrs = append(result.auditErrs, err.Error())
+			return result, nil, err
+		}
+
+		releaseLoad = sync.OnceFunc(func() { <-a.loadSlot })
+		defer releaseLoad()
+	}
 
-	// create new window context
-	windowCtx, cancelWindow := chromedp.NewContext(ctx)
+	// create new window context, in its own browser context so cache and
+	// cookies aren't shared with other sites being audited concurrently
+	windowCtx, cancelWindow := chromedp.NewContext(ctx, chromedp.WithNewBrowserContext())
 	defer cancelWindow()
 
 	// set context timeout
-	timeoutCtx, cancelTimeout := context.WithTimeout(windowCtx, 60*time.Second)
+	timeoutCtx, cancelTimeout := context.WithTimeout(windowCtx, a.throttle.pageTimeout())
 	defer cancelTimeout()
 
 	// open window with blank page and wait to initialise,
@@ -234,7 +642,7 @@ func (a *Audit) runSingle(ctx context.Context, website *Website) auditResult {
 	}))
 	if err != nil {
 		result.auditErrs = append(result.auditErrs, err.Error())
-		return result
+		return result, nil, err
 	}
 
 	// enable page domain and inject JS scripts to run on page
@@ -244,17 +652,20 @@ func (a *Audit) runSingle(ctx context.Context, website *Website) auditResult {
 			return fmt.Errorf("failed to enable page domain: %w", err)
 		}
 
-		if a.checks.lcp.enabled {
-			_, err = page.AddScriptToEvaluateOnNewDocument(lcpScript).Do(ctx)
-			if err != nil {
-				return fmt.Errorf("failed to inject LCP script: %w", err)
-			}
-		}
+		// checks may share scripts, so only inject each once
+		injected := map[string]bool{}
+		for _, check := range checks {
+			for _, script := range check.Scripts() {
+				if injected[script] {
+					continue
+				}
 
-		if a.checks.consoleErrs.enabled || a.checks.requestErrs.enabled {
-			_, err = page.AddScriptToEvaluateOnNewDocument(errScript).Do(ctx)
-			if err != nil {
-				return fmt.Errorf("failed to inject error script: %w", err)
+				_, err = page.AddScriptToEvaluateOnNewDocument(script).Do(ctx)
+				if err != nil {
+					return fmt.Errorf("failed to inject %s script: %w", check.Name(), err)
+				}
+
+				injected[script] = true
 			}
 		}
 
@@ -262,25 +673,22 @@ func (a *Audit) runSingle(ctx context.Context, website *Website) auditResult {
 	}))
 	if err != nil {
 		result.auditErrs = append(result.auditErrs, err.Error())
-		return result
+		return result, nil, err
 	}
 
-	// emulate mobile device
+	// emulate device
 	err = chromedp.Run(
 		timeoutCtx,
-		chromedp.Emulate(chromedp.Device(device.IPhone13)),
+		chromedp.Emulate(device),
 	)
 	if err != nil {
-		result.auditErrs = append(
-			result.auditErrs,
-			fmt.Sprintf("failed to emulate mobile device: %s", err.Error()),
-		)
-
-		return result
+		err = fmt.Errorf("failed to emulate %s device: %w", device.name, err)
+		result.auditErrs = append(result.auditErrs, err.Error())
+		return result, nil, err
 	}
 
-	// enable network domain, and clear cache and cookies
-	err = chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+	// enable network domain, clear cache and cookies, and apply throttling
+	err = chromedp.Run(timeoutCtx, chromedp.ActionFunc(func(ctx context.Context) error {
 		err := network.Enable().Do(ctx)
 		if err != nil {
 			return fmt.Errorf("failed to enable network domain: %w", err)
@@ -296,23 +704,60 @@ func (a *Audit) runSingle(ctx context.Context, website *Website) auditResult {
 			return fmt.Errorf("failed to clear browser cookies: %w", err)
 		}
 
+		err = a.throttle.apply().Do(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to apply %s throttling: %w", a.throttle.name, err)
+		}
+
 		return nil
 	}))
 	if err != nil {
 		result.auditErrs = append(result.auditErrs, err.Error())
-		return result
+		return result, nil, err
 	}
 
-	// force site to load over http in order to check if it auto redirects
-	// (if security check is enabled)
-	websiteScheme := website.scheme
-	if a.checks.secure.enabled {
-		websiteScheme = "http"
+	// record requests made by the page from navigation onwards
+	recorder := newNetworkRecorder()
+	recorder.listen(timeoutCtx)
+
+	// when auditing on multiple devices, the device is used to key per page
+	// output (e.g. screenshots)
+	deviceKey := ""
+	if len(a.devices) > 1 {
+		deviceKey = device.name
+	}
+
+	loadedPage := &auditPage{
+		website:       website,
+		url:           pageURL,
+		device:        deviceKey,
+		requests:      recorder,
+		important:     a.important,
+		screenshotDir: a.screenshotDir,
+		certExpiry:    a.certExpiry,
+		results:       result.results,
+		tracking:      map[string]any{},
+	}
+
+	// let checks start tracking the page load (e.g. code coverage)
+	err = chromedp.Run(timeoutCtx, chromedp.ActionFunc(func(ctx context.Context) error {
+		for _, check := range checks {
+			err := check.Prepare(ctx, loadedPage)
+			if err != nil {
+				return fmt.Errorf("failed to prepare %s check: %w", check.Name(), err)
+			}
+		}
+
+		return nil
+	}))
+	if err != nil {
+		result.auditErrs = append(result.auditErrs, err.Error())
+		return result, nil, err
 	}
 
 	// navigate to site and wait to settle
 	nr, err := chromedp.RunResponse(timeoutCtx, chromedp.ActionFunc(func(ctx context.Context) error {
-		err := chromedp.Navigate(websiteScheme + "://" + website.domain + "/").Do(ctx)
+		err := chromedp.Navigate(pageURL).Do(ctx)
 		if err != nil {
 			return fmt.Errorf("failed to navigate: %w", err)
 		}
@@ -329,7 +774,7 @@ func (a *Audit) runSingle(ctx context.Context, website *Website) auditResult {
 				return fmt.Errorf("failed to wait for page to be idle: %w", err)
 			}
 
-			fmt.Println("⚠️ page's idle check timed out")
+			fmt.Printf("⚠️ %s: page's idle check timed out\n", pageURL)
 		}
 
 		err = chromedp.Sleep(1 * time.Second).Do(ctx)
@@ -339,107 +784,52 @@ func (a *Audit) runSingle(ctx context.Context, website *Website) auditResult {
 
 		return nil
 	}))
+	releaseLoad()
+	if a.har {
+		result.requests = recorder.Requests()
+	}
 	if err != nil {
 		result.auditErrs = append(result.auditErrs, err.Error())
-		return result
+		return result, nil, err
 	}
+	result.httpStatus = nr.Status
+	loadedPage.response = nr
 	if nr.Status >= 400 { // if main document request failed
-		result.auditErrs = append(
-			result.auditErrs,
-			fmt.Sprintf("failed to navigate: HTTP Status - %d", nr.Status),
-		)
-
-		return result
-	}
-
-	// capture missing security headers
-	if a.checks.missingHeaders.enabled {
-		result.checks.missingHeaders.result = a.checkSecurityHeaders(nr.Headers)
+		err = fmt.Errorf("failed to navigate: %w", httpStatusError{status: nr.Status})
+		result.auditErrs = append(result.auditErrs, err.Error())
+		return result, nil, err
 	}
 
-	// perform checks
+	// perform checks - a failing check is recorded against it, without stopping the others
 	err = chromedp.Run(timeoutCtx, chromedp.ActionFunc(func(ctx context.Context) error {
-		// capture site security (is HTTPS)
-		if a.checks.secure.enabled {
-			err := chromedp.Evaluate(securityScript, &result.checks.secure.result).Do(ctx)
-			if err != nil {
-				return fmt.Errorf("failed to evaluate security: %w", err)
-			}
-		}
-
-		// calculate largest contentful paint time
-		if a.checks.lcp.enabled {
-			err := chromedp.Evaluate(`window.__lcp || 0`, &result.checks.lcp.result).Do(ctx)
-			if err != nil {
-				return fmt.Errorf("failed to evaluate LCP: %w", err)
-			}
-		}
-
-		// capture mobile responsiveness issues
-		if a.checks.responsiveIssues.enabled {
-			script := fmt.Sprintf("%s(%t)", responsiveScript, a.important)
-			err = chromedp.Evaluate(script, &result.checks.responsiveIssues.result).Do(ctx)
+		for _, check := range checks {
+			res, err := check.Run(ctx, loadedPage)
 			if err != nil {
-				return fmt.Errorf("failed to evaluate mobile responsiveness: %w", err)
+				result.checkErrs[check.Name()] = err.Error()
+				continue
 			}
-		}
 
-		// collect console errors and warnings
-		if a.checks.consoleErrs.enabled {
-			err = chromedp.Evaluate(`window.__console_errors || []`, &result.checks.consoleErrs.result).Do(ctx)
-			if err != nil {
-				return fmt.Errorf("failed to evaluate console errors: %w", err)
-			}
-		}
-
-		// collect failed requests
-		if a.checks.requestErrs.enabled {
-			err = chromedp.Evaluate(`window.__request_errors || []`, &result.checks.requestErrs.result).Do(ctx)
-			if err != nil {
-				return fmt.Errorf("failed to evaluate request errors: %w", err)
-			}
-		}
-
-		// capture form issues
-		if a.checks.formIssues.enabled {
-			script := fmt.Sprintf("%s(%t)", formScript, a.important)
-			err = chromedp.Evaluate(script, &result.checks.formIssues.result).Do(ctx)
-			if err != nil {
-				return fmt.Errorf("failed to evaluate form issues: %w", err)
-			}
-		}
-
-		// capture common frontend technologies used
-		if a.checks.techStack.enabled {
-			// if important is enabled, only run check if important issues are found
-			hasImportantIssues := len(result.checks.responsiveIssues.result) > 0 ||
-				len(result.checks.formIssues.result) > 0
-
-			if !a.important || hasImportantIssues {
-				err = chromedp.Evaluate(techScript, &result.checks.techStack.result).Do(ctx)
-				if err != nil {
-					return fmt.Errorf("failed to detect tech stack: %w", err)
-				}
-			}
+			result.results[check.Name()] = res
 		}
 
 		return nil
 	}))
 	if err != nil {
 		result.auditErrs = append(result.auditErrs, err.Error())
-		return result
+		return result, nil, err
 	}
 
-	// capture full page screenshot
-	if a.checks.screenshot.enabled {
-		result.checks.screenshot.result, err = a.captureScreenshot(timeoutCtx, website.domain)
-		if err != nil {
-			result.auditErrs = append(result.auditErrs, err.Error())
-			return result
-		}
+	if !discoverLinks {
+		return result, nil, nil
 	}
 
-	return result
+	// collect internal links to crawl next
+	links, err := a.discoverLinks(timeoutCtx)
+	if err != nil {
+		result.auditErrs = append(result.auditErrs, err.Error())
+	}
+
+	return result, links, nil
 }
 
 // waitNetworkIdle returns a chromedp.Action that waits until network is idle,
@@ -494,82 +884,3 @@ func (a *Audit) waitNetworkIdle(idleTime, maxWait time.Duration) chromedp.Action
 		}
 	})
 }
-
-// patterns to ignore during idle check (analytics, tracking, chats, favicons)
-var ignoredIdlePatterns = []string{
-	"google-analytics.com", "googletagmanager.com", "doubleclick.net",
-	"facebook.net", "hotjar.com", "favicon.ico", "google.com/gen_204",
-	"amazon-adsystem.com", "googlesyndication.com", "adsystem.amazon",
-	"facebook.com/tr", "linkedin.com/px", "twitter.com/i/adsct",
-	"pinterest.com/ct", "tiktok.com/i18n", "snapchat.com/p",
-	"scorecardresearch.com", "newrelic.com", "cloudflareinsights.com",
-	"segment.io", "sentry.io", "monorail-edge.shopify.com",
-	"shopifycloud.com", "intercom.io", "zendesk.com", "drift.com",
-	"crisp.chat", "tawk.to", "livechat.com", "freshchat.com",
-	"helpscout.net", "olark.com", "liveperson.net", "pusher.com",
-	"analytics", "telemetry",
-}
-
-// checkSecurityHeaders looks for missing security headers from
-// the page's main document request
-func (a *Audit) checkSecurityHeaders(resHeaders network.Headers) []string {
-	// important security headers to check
-	securityHeaders := []string{
-		"Content-Security-Policy",
-		"Strict-Transport-Security",
-		"X-Content-Type-Options",
-		"X-Frame-Options",
-		"Permissions-Policy",
-		"Referrer-Policy",
-	}
-
-	missingHeaders := []string{}
-
-	for _, header := range securityHeaders {
-		found := false
-
-		for key := range resHeaders {
-			if strings.EqualFold(key, header) {
-				found = true
-				break
-			}
-		}
-
-		if !found {
-			missingHeaders = append(missingHeaders, header)
-		}
-	}
-
-	return missingHeaders
-}
-
-// captureScreenshot takes a full page screenshot and saves it
-// to disk
-func (a *Audit) captureScreenshot(ctx context.Context, domain string) (bool, error) {
-	var screenshot []byte
-
-	err := chromedp.Run(ctx, chromedp.FullScreenshot(&screenshot, 90))
-	if err != nil {
-		return false, fmt.Errorf("failed to capture screenshot: %w", err)
-	}
-
-	// sanitise domain for filesystem
-	safeDomain := a.sanitiseFilename(domain)
-	filename := filepath.Join(a.screenshotDir, fmt.Sprintf("screenshot_%s.jpg", safeDomain))
-	err = os.WriteFile(filename, screenshot, 0644)
-	if err != nil {
-		return false, fmt.Errorf("failed to write screenshot: %w", err)
-	}
-
-	return true, nil
-}
-
-// sanitiseFilename removes characters that could cause filesystem issues
-func (a *Audit) sanitiseFilename(s string) string {
-	// replace problematic characters
-	s = strings.ReplaceAll(s, "/", "_")
-	s = strings.ReplaceAll(s, "\\", "_")
-	s = strings.ReplaceAll(s, ":", "_")
-
-	return s
-}
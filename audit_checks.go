@@ -0,0 +1,646 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+// check to capture site security (is HTTPS)
+var securityCheck = &auditCheck[bool]{
+	name:      "security",
+	important: true,
+	columns:   []string{"Secure"},
+	run: func(ctx context.Context, _ *auditPage) (bool, error) {
+		var secure bool
+		err := chromedp.Evaluate(securityScript, &secure).Do(ctx)
+		if err != nil {
+			return false, fmt.Errorf("failed to evaluate security: %w", err)
+		}
+
+		return secure, nil
+	},
+	values: func(secure bool) []string {
+		return []string{boolToEmoji(secure)}
+	},
+	issues: func(secure bool) int {
+		if !secure {
+			return 1
+		}
+
+		return 0
+	},
+}
+
+// check to inspect the TLS connection and certificate of the page
+var tlsCheck = &auditCheck[certificateInfo]{
+	name: "tls",
+	columns: []string{
+		"TLS Protocol", "TLS Cipher", "Certificate Issuer", "Certificate Subject", "Certificate SANs",
+		"Certificate Expiry", "Certificate Days Left", "Certificate Chain Valid", "Certificate Warnings",
+	},
+	run: func(ctx context.Context, page *auditPage) (certificateInfo, error) {
+		if page.response != nil && !strings.HasPrefix(page.response.URL, "https:") {
+			// loaded over HTTP (e.g. forced by the security check), so the certificate
+			// is read from the site directly - sites without HTTPS are left empty
+			info, err := fetchCertificateInfo(ctx, page.response.URL, page.certExpiry, time.Now())
+			if errors.Is(err, errNoHTTPS) {
+				return certificateInfo{}, nil
+			}
+
+			return info, err
+		}
+
+		return newCertificateInfo(page.response, page.certExpiry, time.Now()), nil
+	},
+	values: func(info certificateInfo) []string {
+		if !info.HTTPS {
+			return make([]string, 9) // site without HTTPS
+		}
+
+		expiry := ""
+		if !info.ValidTo.IsZero() {
+			expiry = info.ValidTo.Format(time.DateOnly)
+		}
+
+		return append(
+			[]string{
+				info.Protocol, info.Cipher, info.Issuer, info.Subject, strings.Join(info.SANs, ", "),
+				expiry, fmt.Sprint(info.DaysRemaining), boolToEmoji(info.ChainValid),
+			},
+			joinValues(info.Warnings)...,
+		)
+	},
+	issues: func(info certificateInfo) int {
+		return len(info.Warnings)
+	},
+}
+
+// check to find subresources of HTTPS pages loaded (or blocked) over HTTP
+var mixedContentCheck = &auditCheck[mixedContent]{
+	name:    mixedContentName,
+	columns: []string{"Active Mixed Content", "Passive Mixed Content"},
+	prepare: startMixedContentTracking,
+	run: func(_ context.Context, page *auditPage) (mixedContent, error) {
+		return findMixedContent(page)
+	},
+	values: func(mixed mixedContent) []string {
+		if !mixed.HTTPS {
+			return make([]string, 2) // not served over HTTPS
+		}
+
+		active := []string{}
+		for _, resource := range mixed.Active {
+			active = append(active, resource.String())
+		}
+
+		passive := []string{}
+		for _, resource := range mixed.Passive {
+			passive = append(passive, resource.String())
+		}
+
+		return append(joinValues(active), joinValues(passive)...)
+	},
+	issues: func(mixed mixedContent) int {
+		return len(mixed.Active) + len(mixed.Passive)
+	},
+}
+
+// check to calculate largest contentful paint time
+var lcpCheck = &auditCheck[float64]{
+	name:    "lcp",
+	scripts: []string{lcpScript},
+	columns: []string{"LCP (ms)", "LCP Rating"},
+	run: func(ctx context.Context, _ *auditPage) (float64, error) {
+		var lcp float64
+		err := chromedp.Evaluate(`window.__lcp || 0`, &lcp).Do(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("failed to evaluate LCP: %w", err)
+		}
+
+		return lcp, nil
+	},
+	values: func(lcp float64) []string {
+		return []string{fmt.Sprint(lcp), string(lcpThresholds.rateTiming(lcp))}
+	},
+	issues: func(lcp float64) int {
+		rating := lcpThresholds.rateTiming(lcp)
+		if rating == ratingNeedsImprovement || rating == ratingPoor {
+			return 1
+		}
+
+		return 0
+	},
+	timings: func(lcp float64) map[string]float64 {
+		if lcp <= 0 {
+			return nil
+		}
+
+		return map[string]float64{"lcp": lcp}
+	},
+}
+
+// check to collect the remaining Core Web Vitals and paint timings
+var vitalsCheck = &auditCheck[webVitals]{
+	name:    "vitals",
+	scripts: []string{vitalsScript},
+	columns: []string{
+		"CLS", "CLS Rating", "FCP (ms)", "FCP Rating", "TTFB (ms)", "TTFB Rating",
+		"TBT (ms)", "TBT Rating", "DOMContentLoaded (ms)", "Load (ms)",
+	},
+	run: func(ctx context.Context, _ *auditPage) (webVitals, error) {
+		var raw struct {
+			CLS              float64 `json:"cls"`
+			FCP              float64 `json:"fcp"`
+			TTFB             float64 `json:"ttfb"`
+			TBT              float64 `json:"tbt"`
+			DOMContentLoaded float64 `json:"domContentLoaded"`
+			Load             float64 `json:"load"`
+		}
+		err := chromedp.Evaluate(vitalsReportScript, &raw).Do(ctx)
+		if err != nil {
+			return webVitals{}, fmt.Errorf("failed to evaluate web vitals: %w", err)
+		}
+
+		return newWebVitals(raw.CLS, raw.FCP, raw.TTFB, raw.TBT, raw.DOMContentLoaded, raw.Load), nil
+	},
+	values: func(vitals webVitals) []string {
+		// unmeasured metrics have no ratings, so are left empty
+		cls, tbt := "", ""
+		if vitals.CLS.Rating != ratingNone {
+			cls = fmt.Sprintf("%.3f", vitals.CLS.Value)
+		}
+		if vitals.TBT.Rating != ratingNone {
+			tbt = fmt.Sprintf("%.0f", vitals.TBT.Value)
+		}
+
+		return []string{
+			cls, string(vitals.CLS.Rating),
+			formatMs(vitals.FCP.Value), string(vitals.FCP.Rating),
+			formatMs(vitals.TTFB.Value), string(vitals.TTFB.Rating),
+			tbt, string(vitals.TBT.Rating),
+			formatMs(vitals.DOMContentLoaded), formatMs(vitals.Load),
+		}
+	},
+	issues:  countPoorVitals,
+	timings: vitalsTimings,
+}
+
+// check to measure main thread long tasks and the scripts responsible - it
+// shares the long tasks observed for TBT by the vitals check
+var longTasksCheck = &auditCheck[mainThreadActivity]{
+	name:    "longtasks",
+	scripts: []string{vitalsScript, scriptTimeScript},
+	columns: []string{"Long Tasks", "Longest Task (ms)", "Script Execution (ms)", "Long Task Scripts"},
+	run: func(ctx context.Context, _ *auditPage) (mainThreadActivity, error) {
+		var raw struct {
+			Tasks   []longTask         `json:"tasks"`
+			Scripts map[string]float64 `json:"scripts"`
+		}
+		err := chromedp.Evaluate(longTasksReportScript, &raw).Do(ctx)
+		if err != nil {
+			return mainThreadActivity{}, fmt.Errorf("failed to evaluate long tasks: %w", err)
+		}
+
+		return newMainThreadActivity(raw.Tasks, raw.Scripts), nil
+	},
+	values: func(activity mainThreadActivity) []string {
+		scripts := []string{}
+		for i, script := range activity.Scripts {
+			if i == maxReportScripts {
+				scripts = append(scripts, fmt.Sprintf("+%d more", len(activity.Scripts)-i))
+				break
+			}
+
+			scripts = append(scripts, script.String())
+		}
+
+		return append(
+			[]string{
+				fmt.Sprint(activity.LongTasks), fmt.Sprintf("%.0f", activity.LongestTaskMs),
+				fmt.Sprintf("%.0f", activity.ScriptMs),
+			},
+			joinValues(scripts)...,
+		)
+	},
+	issues: mainThreadActivity.heavyScripts,
+	timings: func(activity mainThreadActivity) map[string]float64 {
+		return map[string]float64{"longtasks": float64(activity.LongTasks), "longestTask": activity.LongestTaskMs}
+	},
+}
+
+// check to measure how much of the page's JS and CSS goes unused while loading
+var coverageCheck = &auditCheck[codeCoverage]{
+	name:    coverageName,
+	optIn:   true, // instrumenting scripts slows them down, skewing timings
+	columns: []string{"Unused JS", "Unused CSS", "Unused Code", "Unused Code by File"},
+	prepare: startCoverage,
+	run: func(ctx context.Context, page *auditPage) (codeCoverage, error) {
+		return takeCoverage(ctx, page)
+	},
+	values: func(coverage codeCoverage) []string {
+		files := []string{}
+		for i, file := range coverage.Files {
+			if i == maxCoverageFiles {
+				files = append(files, fmt.Sprintf("+%d more", len(coverage.Files)-i))
+				break
+			}
+
+			files = append(files, file.String())
+		}
+
+		return append(
+			[]string{
+				formatUnused(coverage.UnusedScriptBytes, coverage.ScriptBytes),
+				formatUnused(coverage.UnusedStylesheetBytes, coverage.StylesheetBytes),
+				formatUnused(
+					coverage.UnusedScriptBytes+coverage.UnusedStylesheetBytes,
+					coverage.ScriptBytes+coverage.StylesheetBytes,
+				),
+			},
+			joinValues(files)...,
+		)
+	},
+	issues: codeCoverage.wastefulFiles,
+}
+
+// check to measure page weight, from requests made while loading the page
+var weightCheck = &auditCheck[pageWeight]{
+	name: "weight",
+	columns: []string{
+		"Page Weight", "Requests", "Documents", "Scripts", "Stylesheets", "Images",
+		"Fonts", "Media", "XHR", "Other Requests", "First Party", "Third Party",
+	},
+	run: func(_ context.Context, page *auditPage) (pageWeight, error) {
+		return newPageWeight(page.website.domain, page.requests.Requests()), nil
+	},
+	values: func(weight pageWeight) []string {
+		return []string{
+			formatBytes(weight.TotalBytes), fmt.Sprint(weight.Requests),
+			weight.ByType.Document.String(), weight.ByType.Script.String(),
+			weight.ByType.Stylesheet.String(), weight.ByType.Image.String(),
+			weight.ByType.Font.String(), weight.ByType.Media.String(),
+			weight.ByType.XHR.String(), weight.ByType.Other.String(),
+			weight.FirstParty.String(), weight.ThirdParty.String(),
+		}
+	},
+	issues: func(weight pageWeight) int {
+		if weight.TotalBytes > heavyPageBytes {
+			return 1
+		}
+
+		return 0
+	},
+}
+
+// check to find scripts and stylesheets blocking the page's first render
+var blockingCheck = &auditCheck[[]blockingResource]{
+	name:    "blocking",
+	columns: []string{"Render-Blocking Resources"},
+	run: func(ctx context.Context, page *auditPage) ([]blockingResource, error) {
+		var found struct {
+			Resources []blockingCandidate `json:"resources"`
+			FCP       float64             `json:"fcp"`
+		}
+		err := chromedp.Evaluate(blockingScript, &found).Do(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find render blocking resources: %w", err)
+		}
+
+		return findBlockingResources(found.Resources, page.requests, found.FCP), nil
+	},
+	values: formatBlockingResources,
+	issues: func(resources []blockingResource) int {
+		return len(resources)
+	},
+	timings: func(resources []blockingResource) map[string]float64 {
+		// resources load in parallel, so the longest one delays first paint the most
+		delay := 0.0
+		for _, resource := range resources {
+			delay = max(delay, resource.DelayMs)
+		}
+
+		return map[string]float64{"blocking": delay}
+	},
+}
+
+// check to find images that could be smaller, lazy loaded or sized upfront
+var imagesCheck = &auditCheck[imageAudit]{
+	name:    "images",
+	columns: []string{"Image Issues", "Image Savings"},
+	run: func(ctx context.Context, page *auditPage) (imageAudit, error) {
+		var images []pageImage
+		err := chromedp.Evaluate(imagesScript, &images).Do(ctx)
+		if err != nil {
+			return imageAudit{}, fmt.Errorf("failed to collect images: %w", err)
+		}
+
+		return auditImages(images, page.requests), nil
+	},
+	values: func(audit imageAudit) []string {
+		issues := []string{}
+		for _, issue := range audit.Issues {
+			issues = append(issues, issue.String())
+		}
+
+		savings := ""
+		if audit.SavingsBytes > 0 {
+			savings = formatBytes(audit.SavingsBytes)
+		}
+
+		return append(joinValues(issues), savings)
+	},
+	issues: func(audit imageAudit) int {
+		return len(audit.Issues)
+	},
+}
+
+// check to report the impact of each third party on the page
+var thirdPartyCheck = &auditCheck[[]thirdParty]{
+	name:    "thirdparty",
+	scripts: []string{scriptTimeScript},
+	columns: []string{"Third Parties", "Third-Party Main Thread (ms)"},
+	run: func(ctx context.Context, page *auditPage) ([]thirdParty, error) {
+		var scriptTime map[string]float64
+		err := chromedp.Evaluate(`window.__script_time || {}`, &scriptTime).Do(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate script main thread time: %w", err)
+		}
+
+		return thirdPartyImpact(page.website.domain, page.requests.Requests(), scriptTime), nil
+	},
+	values: func(parties []thirdParty) []string {
+		values := []string{}
+		total := 0.0
+		for _, party := range parties {
+			values = append(values, party.String())
+			total += party.MainThreadMs
+		}
+
+		if len(parties) == 0 {
+			return []string{"", ""}
+		}
+
+		return append(joinValues(values), fmt.Sprintf("%.0f", total))
+	},
+	issues: func(parties []thirdParty) int {
+		issues := 0
+		for _, party := range parties {
+			if party.MainThreadMs > heavyThirdPartyMs {
+				issues++
+			}
+		}
+
+		return issues
+	},
+}
+
+// check to collect console errors and warnings
+var consoleCheck = &auditCheck[[]string]{
+	name:    "console",
+	scripts: []string{errScript},
+	columns: []string{"Console Errors"},
+	run: func(ctx context.Context, _ *auditPage) ([]string, error) {
+		var consoleErrs []string
+		err := chromedp.Evaluate(`window.__console_errors || []`, &consoleErrs).Do(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate console errors: %w", err)
+		}
+
+		return consoleErrs, nil
+	},
+	values: joinValues,
+	issues: countValues,
+}
+
+// check to collect failed requests
+var requestCheck = &auditCheck[[]string]{
+	name:    "request",
+	scripts: []string{errScript},
+	columns: []string{"Request Errors"},
+	run: func(ctx context.Context, _ *auditPage) ([]string, error) {
+		var requestErrs []string
+		err := chromedp.Evaluate(`window.__request_errors || []`, &requestErrs).Do(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate request errors: %w", err)
+		}
+
+		return requestErrs, nil
+	},
+	values: joinValues,
+	issues: countValues,
+}
+
+// check to capture missing security headers
+var headersCheck = &auditCheck[securityHeaders]{
+	name:    "headers",
+	columns: []string{"Headers Grade", "Headers Score", "Missing Headers", "Header Findings"},
+	run: func(_ context.Context, page *auditPage) (securityHeaders, error) {
+		return gradeSecurityHeaders(page.response.Headers), nil
+	},
+	values: func(headers securityHeaders) []string {
+		findings := []string{}
+		for _, finding := range headers.Findings {
+			if finding.Score < finding.MaxScore {
+				findings = append(findings, finding.String())
+			}
+		}
+
+		return []string{
+			headers.Grade,
+			fmt.Sprint(headers.Score),
+			strings.Join(headers.Missing, ";\n"),
+			strings.Join(findings, ";\n"),
+		}
+	},
+	issues: securityHeaders.issues,
+}
+
+// check to capture missing compression and caching of the page's responses
+var cachingCheck = &auditCheck[cachingAudit]{
+	name: "caching",
+	columns: []string{
+		"Document Compressed", "Uncompressed Assets", "Short Cache Lifetimes", "Missing Validators",
+	},
+	run: func(_ context.Context, page *auditPage) (cachingAudit, error) {
+		return auditCaching(page.response, page.requests.Requests()), nil
+	},
+	values: func(audit cachingAudit) []string {
+		return []string{
+			boolToEmoji(audit.DocumentCompressed),
+			joinFindings(audit.Uncompressed),
+			joinFindings(audit.ShortCache),
+			joinFindings(audit.NoValidators),
+		}
+	},
+	issues: func(audit cachingAudit) int {
+		issues := len(audit.Uncompressed) + len(audit.ShortCache) + len(audit.NoValidators)
+		if !audit.DocumentCompressed {
+			issues++
+		}
+
+		return issues
+	},
+}
+
+// check to capture mobile responsiveness issues
+var mobileCheck = &auditCheck[[]string]{
+	name:      "mobile",
+	important: true,
+	columns:   []string{"Responsive Issues"},
+	run: func(ctx context.Context, page *auditPage) ([]string, error) {
+		var responsiveIssues []string
+		script := fmt.Sprintf("%s(%t)", responsiveScript, page.important)
+		err := chromedp.Evaluate(script, &responsiveIssues).Do(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate mobile responsiveness: %w", err)
+		}
+
+		return responsiveIssues, nil
+	},
+	values: joinValues,
+	issues: countResponsiveIssues,
+}
+
+// check to capture form issues
+var formCheck = &auditCheck[[]string]{
+	name:      "form",
+	important: true,
+	columns:   []string{"Form Issues"},
+	run: func(ctx context.Context, page *auditPage) ([]string, error) {
+		var formIssues []string
+		script := fmt.Sprintf("%s(%t)", formScript, page.important)
+		err := chromedp.Evaluate(script, &formIssues).Do(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate form issues: %w", err)
+		}
+
+		return formIssues, nil
+	},
+	values: joinValues,
+	issues: countValues,
+}
+
+// check to capture common frontend technologies used
+var techCheck = &auditCheck[[]string]{
+	name:      "tech",
+	important: true,
+	columns:   []string{"Detected Tech"},
+	run: func(ctx context.Context, page *auditPage) ([]string, error) {
+		// if important is enabled, only run check if important issues are found
+		responsiveIssues, _ := page.results[mobileCheck.Name()].([]string)
+		formIssues, _ := page.results[formCheck.Name()].([]string)
+		hasImportantIssues := len(responsiveIssues) > 0 || len(formIssues) > 0
+
+		if page.important && !hasImportantIssues {
+			return nil, nil
+		}
+
+		var techStack []string
+		err := chromedp.Evaluate(techScript, &techStack).Do(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to detect tech stack: %w", err)
+		}
+
+		return techStack, nil
+	},
+	values: joinValues,
+}
+
+// check to capture full page screenshot (result is the saved file's path)
+var screenshotCheck = &auditCheck[string]{
+	name:    "screenshot",
+	columns: []string{"Screenshot"},
+	run: func(ctx context.Context, page *auditPage) (string, error) {
+		return captureScreenshot(ctx, page.screenshotDir, page.name())
+	},
+	values: func(path string) []string {
+		return []string{boolToEmoji(path != "")}
+	},
+}
+
+// joinValues formats a multi-value result into a single output column value
+func joinValues(result []string) []string {
+	return []string{strings.Join(result, ";\n")}
+}
+
+// countValues counts each value of a multi-value result as an issue
+func countValues(result []string) int {
+	return len(result)
+}
+
+// countResponsiveIssues counts mobile responsiveness issues, excluding
+// the overall score entry
+func countResponsiveIssues(result []string) int {
+	issues := 0
+	for _, issue := range result {
+		if !strings.HasPrefix(issue, "Score:") {
+			issues++
+		}
+	}
+
+	return issues
+}
+
+// responsiveScore extracts the overall score from mobile responsiveness issues
+// (only reported when not running important checks)
+func responsiveScore(result []string) (int, bool) {
+	for _, issue := range result {
+		scoreStr, ok := strings.CutPrefix(issue, "Score: ")
+		if !ok {
+			continue
+		}
+
+		fields := strings.Fields(scoreStr)
+		if len(fields) == 0 {
+			return 0, false
+		}
+
+		score, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return 0, false
+		}
+
+		return score, true
+	}
+
+	return 0, false
+}
+
+// captureScreenshot takes a full page screenshot and saves it
+// to disk, returning the file path
+func captureScreenshot(ctx context.Context, screenshotDir, name string) (string, error) {
+	var screenshot []byte
+
+	err := chromedp.FullScreenshot(&screenshot, 90).Do(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to capture screenshot: %w", err)
+	}
+
+	// sanitise name for filesystem
+	safeName := sanitiseFilename(name)
+	filename := filepath.Join(screenshotDir, fmt.Sprintf("screenshot_%s.jpg", safeName))
+	err = os.WriteFile(filename, screenshot, 0644)
+	if err != nil {
+		return "", fmt.Errorf("failed to write screenshot: %w", err)
+	}
+
+	return filename, nil
+}
+
+// sanitiseFilename removes characters that could cause filesystem issues
+func sanitiseFilename(s string) string {
+	// replace problematic characters
+	s = strings.ReplaceAll(s, "/", "_")
+	s = strings.ReplaceAll(s, "\\", "_")
+	s = strings.ReplaceAll(s, ":", "_")
+
+	return s
+}
@@ -23,6 +23,140 @@ const lcpScript = `(() => {
 	}).observe({ type: "largest-contentful-paint", buffered: true });
 })();`
 
+// script to collect layout shifts, first contentful paint and long tasks,
+// for the remaining Core Web Vitals
+const vitalsScript = `(() => {
+	window.__vitals = { cls: 0, fcp: 0, longTasks: [] };
+
+	// CLS is the largest session window of shifts (gaps under 1s, up to 5s long)
+	let sessionValue = 0;
+	let sessionEntries = [];
+	new PerformanceObserver((list) => {
+		for (const entry of list.getEntries()) {
+			if (entry.hadRecentInput) continue; // shifts caused by user input don't count
+
+			const first = sessionEntries[0];
+			const last = sessionEntries[sessionEntries.length - 1];
+			if (last && entry.startTime - last.startTime < 1000 && entry.startTime - first.startTime < 5000) {
+				sessionValue += entry.value;
+				sessionEntries.push(entry);
+			} else {
+				sessionValue = entry.value;
+				sessionEntries = [entry];
+			}
+
+			window.__vitals.cls = Math.max(window.__vitals.cls, sessionValue);
+		}
+	}).observe({ type: "layout-shift", buffered: true });
+
+	new PerformanceObserver((list) => {
+		for (const entry of list.getEntries()) {
+			if (entry.name === "first-contentful-paint") {
+				window.__vitals.fcp = entry.startTime;
+			}
+		}
+	}).observe({ type: "paint", buffered: true });
+
+	new PerformanceObserver((list) => {
+		for (const entry of list.getEntries()) {
+			window.__vitals.longTasks.push({ start: entry.startTime, duration: entry.duration });
+		}
+	}).observe({ type: "longtask", buffered: true });
+})();`
+
+// script to read collected vitals, along with navigation timings - total
+// blocking time is summed from long tasks after first contentful paint
+const vitalsReportScript = `(() => {
+	const vitals = window.__vitals || { cls: 0, fcp: 0, longTasks: [] };
+	const nav = performance.getEntriesByType("navigation")[0] || {};
+
+	const tbt = vitals.longTasks
+		.filter(task => task.start >= vitals.fcp)
+		.reduce((total, task) => total + Math.max(0, task.duration - 50), 0);
+
+	return {
+		cls: vitals.cls,
+		fcp: vitals.fcp,
+		ttfb: nav.responseStart || 0,
+		tbt: tbt,
+		domContentLoaded: nav.domContentLoadedEventEnd || 0,
+		load: nav.loadEventEnd || 0,
+	};
+})();`
+
+// script to find synchronous scripts and stylesheets in the head, which block
+// first render, along with the first contentful paint time
+const blockingScript = `(() => {
+	const resources = [];
+
+	document.querySelectorAll('head script[src]').forEach((el) => {
+		if (el.async || el.defer || el.type === 'module') return; // don't block parsing
+		resources.push({ url: el.src, type: 'script' });
+	});
+
+	document.querySelectorAll('head link[rel~="stylesheet"][href]').forEach((el) => {
+		if (el.disabled) return;
+		if (el.media && !window.matchMedia(el.media).matches) return; // e.g. print styles don't block
+		resources.push({ url: el.href, type: 'stylesheet' });
+	});
+
+	const fcp = performance.getEntriesByName('first-contentful-paint')[0];
+	return { resources: resources, fcp: fcp ? fcp.startTime : 0 };
+})();`
+
+// script to collect images on the page, with their natural and rendered sizes
+const imagesScript = `(() => {
+	const viewportHeight = window.innerHeight;
+	const dpr = window.devicePixelRatio || 1;
+
+	return Array.from(document.images)
+		.filter(img => (img.currentSrc || img.src) && !(img.currentSrc || img.src).startsWith('data:'))
+		.map((img) => {
+			const rect = img.getBoundingClientRect();
+			const aspectRatio = getComputedStyle(img).aspectRatio;
+
+			return {
+				url: img.currentSrc || img.src,
+				naturalWidth: img.naturalWidth,
+				naturalHeight: img.naturalHeight,
+				// rendered size in device pixels
+				renderedWidth: Math.round(rect.width * dpr),
+				renderedHeight: Math.round(rect.height * dpr),
+				visible: rect.width > 0 && rect.height > 0,
+				belowFold: rect.top + window.scrollY >= viewportHeight,
+				lazy: img.loading === 'lazy',
+				// explicit size attributes, or a CSS aspect ratio, reserve space before loading
+				hasDimensions: (img.hasAttribute('width') && img.hasAttribute('height')) ||
+					(aspectRatio !== 'auto' && !aspectRatio.startsWith('auto')),
+			};
+		});
+})();`
+
+// script to collect main thread time of each script, attributed by long
+// animation frames (only frames over 50ms are reported) - inline scripts have
+// no source URL, so are grouped as "inline"
+const scriptTimeScript = `(() => {
+	window.__script_time = {};
+
+	new PerformanceObserver((list) => {
+		for (const frame of list.getEntries()) {
+			for (const script of frame.scripts || []) {
+				const url = script.sourceURL || 'inline';
+				if (url !== 'inline' && !url.startsWith('http')) continue;
+
+				window.__script_time[url] = (window.__script_time[url] || 0) + script.duration;
+			}
+		}
+	}).observe({ type: "long-animation-frame", buffered: true });
+})();`
+
+// script to read long tasks collected by vitalsScript, along with the main
+// thread time of each script collected by scriptTimeScript
+const longTasksReportScript = `(() => {
+	const vitals = window.__vitals || { longTasks: [] };
+	return { tasks: vitals.longTasks, scripts: window.__script_time || {} };
+})();`
+
 // script to capture console errors and warnings, and request errors
 const errScript = `(() => {
 	window.__console_errors = [];
@@ -568,3 +702,10 @@ const techScript = `(() => {
 
 	return __detectedTech;
 })();`
+
+// script to collect links for crawling
+const linksScript = `(() => {
+	return Array.from(document.querySelectorAll('a[href]'))
+		.map(a => a.href)
+		.filter(href => href.startsWith('http'));
+})();`
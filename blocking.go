@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+)
+
+// blockingResource is a script or stylesheet that blocked the page's first render
+type blockingResource struct {
+	URL      string  `json:"url"`
+	Type     string  `json:"type"` // "script" or "stylesheet"
+	Bytes    int64   `json:"bytes"`
+	DelayMs  float64 `json:"delayMs"` // how long it was loading before first paint
+	Priority string  `json:"priority,omitempty"`
+}
+
+// String formats the resource for output
+func (r blockingResource) String() string {
+	return fmt.Sprintf("%s (%s, %s, %.0f ms)", r.URL, r.Type, formatBytes(r.Bytes), r.DelayMs)
+}
+
+// blockingCandidate is a head script or stylesheet found in the DOM
+type blockingCandidate struct {
+	URL  string `json:"url"`
+	Type string `json:"type"`
+}
+
+// findBlockingResources matches render blocking candidates found in the DOM to
+// their network requests, for their size and load timing - candidates Chrome
+// loaded with low priority (so didn't treat as blocking) are left out
+func findBlockingResources(
+	candidates []blockingCandidate,
+	requests *networkRecorder,
+	fcpMs float64,
+) []blockingResource {
+	navStart, hasNavStart := requests.navigationStart()
+	firstPaint := navStart.Add(time.Duration(fcpMs * float64(time.Millisecond)))
+
+	resources := []blockingResource{}
+	for _, candidate := range candidates {
+		resource := blockingResource{URL: candidate.URL, Type: candidate.Type}
+
+		req, ok := requests.find(candidate.URL)
+		if ok {
+			if !isBlockingPriority(req.request.InitialPriority) {
+				continue
+			}
+
+			resource.Bytes = req.transferred
+			resource.Priority = string(req.request.InitialPriority)
+
+			// time spent loading before first paint (or in total, if it's unknown)
+			end := req.finishedAt
+			if hasNavStart && fcpMs > 0 && firstPaint.Before(end) {
+				end = firstPaint
+			}
+			if !req.sentAt.IsZero() && end.After(req.sentAt) {
+				resource.DelayMs = float64(end.Sub(req.sentAt)) / float64(time.Millisecond)
+			}
+		}
+
+		resources = append(resources, resource)
+	}
+
+	return resources
+}
+
+// isBlockingPriority reports whether Chrome loads resources with the given
+// priority ahead of first render
+func isBlockingPriority(priority network.ResourcePriority) bool {
+	return priority == network.ResourcePriorityVeryHigh || priority == network.ResourcePriorityHigh
+}
+
+// formatBlockingResources formats render blocking resources into a single
+// output column value
+func formatBlockingResources(resources []blockingResource) []string {
+	values := []string{}
+	for _, resource := range resources {
+		values = append(values, resource.String())
+	}
+
+	return joinValues(values)
+}
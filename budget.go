@@ -0,0 +1,230 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// budgetMetric is a metric that budgets can be set for, read from
+// a page's check results (or the median of its samples, for timing metrics
+// sampled across runs)
+type budgetMetric struct {
+	name  string
+	check string // check the metric is read from (empty if none is needed)
+	unit  string
+	value func(res auditResult, page pageResult) (float64, bool) // false if not measured
+}
+
+// budgetMetrics lists all metrics budgets can be set for, in output order
+var budgetMetrics = []budgetMetric{
+	{name: "lcp", check: lcpCheck.Name(), unit: "ms", value: func(_ auditResult, page pageResult) (float64, bool) {
+		lcp, ok := page.results[lcpCheck.Name()].(float64)
+		return lcp, ok && lcp > 0
+	}},
+	{name: "cls", check: vitalsCheck.Name(), value: vitalValue(func(v webVitals) vitalMetric { return v.CLS })},
+	{name: "fcp", check: vitalsCheck.Name(), unit: "ms", value: vitalValue(func(v webVitals) vitalMetric { return v.FCP })},
+	{name: "ttfb", check: vitalsCheck.Name(), unit: "ms", value: vitalValue(func(v webVitals) vitalMetric { return v.TTFB })},
+	{name: "tbt", check: vitalsCheck.Name(), unit: "ms", value: vitalValue(func(v webVitals) vitalMetric { return v.TBT })},
+	{name: "longtasks", check: longTasksCheck.Name(), value: func(_ auditResult, page pageResult) (float64, bool) {
+		activity, ok := page.results[longTasksCheck.Name()].(mainThreadActivity)
+		return float64(activity.LongTasks), ok
+	}},
+	{name: "weight", check: weightCheck.Name(), unit: "KB", value: func(_ auditResult, page pageResult) (float64, bool) {
+		weight, ok := page.results[weightCheck.Name()].(pageWeight)
+		return float64(weight.TotalBytes) / 1024, ok
+	}},
+	{name: "requests", check: weightCheck.Name(), value: func(_ auditResult, page pageResult) (float64, bool) {
+		weight, ok := page.results[weightCheck.Name()].(pageWeight)
+		return float64(weight.Requests), ok
+	}},
+	{name: "console", check: consoleCheck.Name(), value: countValue(consoleCheck.Name())},
+	{name: "request", check: requestCheck.Name(), value: countValue(requestCheck.Name())},
+	{name: "headers", check: headersCheck.Name(), value: func(_ auditResult, page pageResult) (float64, bool) {
+		headers, ok := page.results[headersCheck.Name()].(securityHeaders)
+		return float64(len(headers.Missing)), ok
+	}},
+	{name: "headerFindings", check: headersCheck.Name(), value: func(_ auditResult, page pageResult) (float64, bool) {
+		headers, ok := page.results[headersCheck.Name()].(securityHeaders)
+		return float64(headers.issues()), ok
+	}},
+	{name: "mobile", check: mobileCheck.Name(), value: func(_ auditResult, page pageResult) (float64, bool) {
+		responsiveIssues, ok := page.results[mobileCheck.Name()].([]string)
+		return float64(countResponsiveIssues(responsiveIssues)), ok
+	}},
+	{name: "form", check: formCheck.Name(), value: countValue(formCheck.Name())},
+	{name: "issues", value: func(res auditResult, page pageResult) (float64, bool) {
+		// pages that failed to load have no check results to count issues from
+		measured := page.failure == failureNone || page.failure == failureCheckScript
+		return float64(res.pageIssues(page)), measured
+	}},
+}
+
+// vitalValue returns a metric value getter for one of the web vitals
+func vitalValue(metric func(webVitals) vitalMetric) func(auditResult, pageResult) (float64, bool) {
+	return func(_ auditResult, page pageResult) (float64, bool) {
+		vitals, ok := page.results[vitalsCheck.Name()].(webVitals)
+		if !ok {
+			return 0, false
+		}
+
+		vital := metric(vitals)
+		return vital.Value, vital.Rating != ratingNone
+	}
+}
+
+// countValue returns a metric value getter counting a multi-value check result
+func countValue(check string) func(auditResult, pageResult) (float64, bool) {
+	return func(_ auditResult, page pageResult) (float64, bool) {
+		values, ok := page.results[check].([]string)
+		return float64(len(values)), ok
+	}
+}
+
+// budget is the maximum allowed value of a metric
+type budget struct {
+	metric budgetMetric
+	limit  float64
+}
+
+// label returns the budget formatted for output (e.g. "lcp <= 2500ms")
+func (b budget) label() string {
+	return fmt.Sprintf("%s <= %g%s", b.metric.name, b.limit, b.metric.unit)
+}
+
+// loadBudgets reads budgets from a YAML or JSON file, mapping metric names to
+// their maximum allowed values (an empty path means no budgets are set)
+func loadBudgets(path string) ([]budget, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	limits := map[string]float64{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &limits)
+	case ".json":
+		err = json.Unmarshal(data, &limits)
+	default:
+		return nil, fmt.Errorf("unsupported budgets file format: %s (use .yaml, .yml or .json)", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse file: %w", err)
+	}
+
+	for name := range limits {
+		if _, ok := lookupBudgetMetric(name); !ok {
+			return nil, fmt.Errorf("unknown budget metric: %s", name)
+		}
+	}
+
+	// keep budgets in metric order, so output is stable
+	budgets := []budget{}
+	for _, metric := range budgetMetrics {
+		if limit, ok := limits[metric.name]; ok {
+			budgets = append(budgets, budget{metric: metric, limit: limit})
+		}
+	}
+
+	if len(budgets) == 0 {
+		return nil, fmt.Errorf("no budgets specified")
+	}
+
+	return budgets, nil
+}
+
+// lookupBudgetMetric finds a budget metric by name
+func lookupBudgetMetric(name string) (budgetMetric, bool) {
+	for _, metric := range budgetMetrics {
+		if metric.name == name {
+			return metric, true
+		}
+	}
+
+	return budgetMetric{}, false
+}
+
+// budgetResult holds the evaluation of a single budget for an audited site,
+// against its worst page
+type budgetResult struct {
+	Budget   string  `json:"budget"`
+	Metric   string  `json:"metric"`
+	Limit    float64 `json:"limit"`
+	Value    float64 `json:"value"`
+	Page     string  `json:"page,omitempty"` // page the value was measured on
+	Measured bool    `json:"measured"`       // unmeasured budgets (e.g. failed pages) fail
+	Passed   bool    `json:"passed"`
+}
+
+// evaluateBudgets checks every budget against each page of the result,
+// keeping the worst value
+func evaluateBudgets(budgets []budget, res auditResult) []budgetResult {
+	results := []budgetResult{}
+	for _, b := range budgets {
+		result := budgetResult{Budget: b.label(), Metric: b.metric.name, Limit: b.limit, Measured: true}
+
+		for _, page := range res.pages {
+			value, ok := b.metric.value(res, page)
+			if stats, sampled := page.timings[b.metric.name]; sampled {
+				value, ok = stats.Median, true
+			}
+
+			if !ok {
+				result.Measured, result.Value, result.Page = false, 0, page.url
+				break
+			}
+
+			if result.Page == "" || value > result.Value {
+				result.Value, result.Page = math.Round(value*1000)/1000, page.url
+			}
+		}
+
+		result.Passed = result.Measured && result.Value <= b.limit
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// budgetsPassed reports whether the result is within all of its budgets
+func (r auditResult) budgetsPassed() bool {
+	for _, budget := range r.budgets {
+		if !budget.Passed {
+			return false
+		}
+	}
+
+	return true
+}
+
+// budgetVerdict returns the overall budgets verdict of the result
+// (empty if no budgets are set)
+func (r auditResult) budgetVerdict() string {
+	switch {
+	case len(r.budgets) == 0:
+		return ""
+	case r.budgetsPassed():
+		return "pass"
+	default:
+		return "fail"
+	}
+}
+
+// formatValue formats the measured value for output
+func (r budgetResult) formatValue() string {
+	if !r.Measured {
+		return "❌ not measured"
+	}
+
+	return fmt.Sprintf("%s %g", boolToEmoji(r.Passed), r.Value)
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+)
+
+// static assets cached for less than this are reported
+const minCacheLifetime = 7 * 24 * time.Hour
+
+// responses smaller than this gain little from compression (same cut-off as Lighthouse)
+const minCompressibleBytes = 1400
+
+// resource types of static assets, which should be cached
+var staticResourceTypes = []network.ResourceType{
+	network.ResourceTypeScript, network.ResourceTypeStylesheet, network.ResourceTypeImage,
+	network.ResourceTypeFont, network.ResourceTypeMedia,
+}
+
+// MIME types of text based responses, which should be compressed
+// (other than "text/*" ones)
+var compressibleMimeTypes = []string{
+	"application/javascript", "application/x-javascript", "application/json",
+	"application/ld+json", "application/manifest+json", "application/xml",
+	"image/svg+xml", "image/x-icon", "image/vnd.microsoft.icon",
+}
+
+// content encodings that count as compressed
+var compressedEncodings = []string{"gzip", "br", "zstd", "deflate"}
+
+// assetFinding is a response found with a caching or compression issue
+type assetFinding struct {
+	URL    string `json:"url"`
+	Bytes  int64  `json:"bytes"`
+	Detail string `json:"detail"`
+}
+
+// String formats the finding for output
+func (f assetFinding) String() string {
+	return fmt.Sprintf("%s (%s, %s)", f.URL, formatBytes(f.Bytes), f.Detail)
+}
+
+// joinFindings formats findings into a single output column value
+func joinFindings(findings []assetFinding) string {
+	values := []string{}
+	for _, finding := range findings {
+		values = append(values, finding.String())
+	}
+
+	return strings.Join(values, ";\n")
+}
+
+// cachingAudit holds the caching and compression issues of a page's responses
+type cachingAudit struct {
+	DocumentCompressed bool           `json:"documentCompressed"`
+	Uncompressed       []assetFinding `json:"uncompressed"` // text based subresources
+	ShortCache         []assetFinding `json:"shortCache"`   // static assets with no or short lifetimes
+	NoValidators       []assetFinding `json:"noValidators"` // static assets without ETag/Last-Modified
+}
+
+// auditCaching checks the main document and subresource responses for missing
+// compression, short cache lifetimes and missing validators
+func auditCaching(document *network.Response, requests []networkRequest) cachingAudit {
+	audit := cachingAudit{
+		DocumentCompressed: isCompressed(document.Headers),
+		Uncompressed:       []assetFinding{},
+		ShortCache:         []assetFinding{},
+		NoValidators:       []assetFinding{},
+	}
+
+	for _, req := range requests {
+		// only look at successfully loaded subresources
+		if !req.finished || req.response == nil || req.response.Status != http.StatusOK {
+			continue
+		}
+		if req.url == document.URL {
+			continue
+		}
+
+		headers := req.response.Headers
+
+		if isCompressible(req) && req.transferred >= minCompressibleBytes && !isCompressed(headers) {
+			audit.Uncompressed = append(audit.Uncompressed, assetFinding{
+				URL:    req.url,
+				Bytes:  req.transferred,
+				Detail: "served without gzip/brotli",
+			})
+		}
+
+		if !slices.Contains(staticResourceTypes, req.resourceType) {
+			continue
+		}
+
+		lifetime, policy := cacheLifetime(headers)
+		if lifetime < minCacheLifetime {
+			audit.ShortCache = append(audit.ShortCache, assetFinding{URL: req.url, Bytes: req.transferred, Detail: policy})
+		}
+
+		if headerValue(headers, "ETag") == "" && headerValue(headers, "Last-Modified") == "" {
+			audit.NoValidators = append(audit.NoValidators, assetFinding{
+				URL:    req.url,
+				Bytes:  req.transferred,
+				Detail: "no ETag or Last-Modified",
+			})
+		}
+	}
+
+	return audit
+}
+
+// isCompressible reports whether the request's response is text based
+func isCompressible(req networkRequest) bool {
+	switch req.resourceType {
+	case network.ResourceTypeDocument, network.ResourceTypeScript, network.ResourceTypeStylesheet:
+		return true
+	}
+
+	mimeType := strings.ToLower(req.response.MimeType)
+	return strings.HasPrefix(mimeType, "text/") || slices.Contains(compressibleMimeTypes, mimeType)
+}
+
+// isCompressed reports whether the response headers show a compressed body
+func isCompressed(headers network.Headers) bool {
+	for encoding := range strings.SplitSeq(headerValue(headers, "Content-Encoding"), ",") {
+		if slices.Contains(compressedEncodings, strings.ToLower(strings.TrimSpace(encoding))) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// cacheLifetime returns how long the response can be cached for, from its
+// Cache-Control or Expires headers, along with the policy it was read from
+func cacheLifetime(headers network.Headers) (time.Duration, string) {
+	cacheControl := strings.ToLower(headerValue(headers, "Cache-Control"))
+
+	for directive := range strings.SplitSeq(cacheControl, ",") {
+		directive = strings.TrimSpace(directive)
+		if directive == "no-store" || directive == "no-cache" {
+			return 0, "Cache-Control: " + directive
+		}
+
+		seconds, ok := strings.CutPrefix(directive, "max-age=")
+		if !ok {
+			continue
+		}
+
+		maxAge, err := strconv.Atoi(strings.Trim(seconds, `"`))
+		if err != nil {
+			continue
+		}
+
+		return time.Duration(maxAge) * time.Second, "Cache-Control: " + directive
+	}
+
+	expires, err := http.ParseTime(headerValue(headers, "Expires"))
+	if err != nil {
+		return 0, "no Cache-Control or Expires lifetime"
+	}
+
+	// lifetime is relative to the response date (or now, if it's missing)
+	date, err := http.ParseTime(headerValue(headers, "Date"))
+	if err != nil {
+		date = time.Now()
+	}
+
+	return expires.Sub(date), "Expires: " + headerValue(headers, "Expires")
+}
+
+// headerValue returns the value of a header, matched case-insensitively
+// (multiple values are joined, as CDP does)
+func headerValue(headers network.Headers, name string) string {
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return fmt.Sprint(value)
+		}
+	}
+
+	return ""
+}
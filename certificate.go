@@ -0,0 +1,246 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/security"
+)
+
+const certificateTimeout = 10 * time.Second // for connecting to fetch a certificate directly
+
+// errNoHTTPS is returned when nothing accepts connections on the site's HTTPS port
+var errNoHTTPS = errors.New("site doesn't serve HTTPS")
+
+// TLS protocol versions browsers have deprecated
+var legacyTLSProtocols = []string{"SSL 3.0", "TLS 1.0", "TLS 1.1"}
+
+// certificateInfo holds the TLS connection and certificate details of the
+// page's main document
+type certificateInfo struct {
+	HTTPS          bool      `json:"https"` // the rest is only set for pages served over HTTPS
+	Protocol       string    `json:"protocol,omitempty"`
+	Cipher         string    `json:"cipher,omitempty"`
+	Issuer         string    `json:"issuer,omitempty"`
+	Subject        string    `json:"subject,omitempty"`
+	SANs           []string  `json:"sans,omitempty"`
+	ValidFrom      time.Time `json:"validFrom,omitzero"`
+	ValidTo        time.Time `json:"validTo,omitzero"`
+	DaysRemaining  int       `json:"daysRemaining"`
+	ChainValid     bool      `json:"chainValid"`
+	UncoveredHosts []string  `json:"uncoveredHosts,omitempty"` // the page's host and its www/apex counterpart, if not covered
+	Warnings       []string  `json:"warnings,omitempty"`
+}
+
+// newCertificateInfo reads certificate details from the main document response,
+// warning about certificates expiring within the given number of days
+func newCertificateInfo(response *network.Response, expiryDays int, now time.Time) certificateInfo {
+	if response == nil || response.SecurityDetails == nil || !strings.HasPrefix(response.URL, "https:") {
+		return certificateInfo{}
+	}
+
+	details := response.SecurityDetails
+	info := certificateInfo{
+		HTTPS:    true,
+		Protocol: details.Protocol,
+		Cipher:   details.Cipher,
+		Issuer:   details.Issuer,
+		Subject:  details.SubjectName,
+		SANs:     details.SanList,
+		// Chrome refuses to load pages with untrusted chains, unless they're
+		// loaded with certificate errors ignored
+		ChainValid: response.SecurityState != security.StateInsecure,
+	}
+
+	// TLS 1.3 negotiates the key exchange separately, so it isn't reported
+	if details.KeyExchange != "" {
+		info.Cipher = details.KeyExchange + " " + info.Cipher
+	}
+
+	if details.ValidFrom != nil {
+		info.ValidFrom = details.ValidFrom.Time()
+	}
+	if details.ValidTo != nil {
+		info.ValidTo = details.ValidTo.Time()
+	}
+
+	if parsed, err := url.Parse(response.URL); err == nil {
+		info.inspect(parsed.Hostname(), expiryDays, now)
+	}
+
+	return info
+}
+
+// fetchCertificateInfo connects to the page's host over TLS to read its
+// certificate directly, for pages Chrome didn't load over HTTPS - either because
+// it rejected the certificate, or the page was loaded over HTTP. The chain is
+// verified at a time the certificate is valid, so trust is reported apart
+// from expiry. Sites refusing connections (or with nothing listening) on the
+// HTTPS port give errNoHTTPS, while failed handshakes are returned as errors
+func fetchCertificateInfo(ctx context.Context, pageURL string, expiryDays int, now time.Time) (certificateInfo, error) {
+	parsed, err := url.Parse(pageURL)
+	if err != nil {
+		return certificateInfo{}, fmt.Errorf("failed to parse page URL: %w", err)
+	}
+
+	host, port := parsed.Hostname(), "443"
+	if parsed.Scheme == "https" && parsed.Port() != "" {
+		port = parsed.Port()
+	}
+
+	dialCtx, cancel := context.WithTimeout(ctx, certificateTimeout)
+	defer cancel()
+
+	rawConn, err := (&net.Dialer{}).DialContext(dialCtx, "tcp", net.JoinHostPort(host, port))
+	if err != nil {
+		if errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, os.ErrDeadlineExceeded) {
+			return certificateInfo{}, errNoHTTPS
+		}
+
+		return certificateInfo{}, fmt.Errorf("failed to connect: %w", err)
+	}
+
+	// verification is skipped here so rejected certificates can still be read
+	conn := tls.Client(rawConn, &tls.Config{ServerName: host, InsecureSkipVerify: true})
+	defer conn.Close()
+
+	err = conn.HandshakeContext(dialCtx)
+	if err != nil {
+		return certificateInfo{}, fmt.Errorf("failed TLS handshake: %w", err)
+	}
+
+	state := conn.ConnectionState()
+	if len(state.PeerCertificates) == 0 {
+		return certificateInfo{}, fmt.Errorf("no certificate was presented")
+	}
+
+	leaf := state.PeerCertificates[0]
+	info := certificateInfo{
+		HTTPS:     true,
+		Protocol:  tls.VersionName(state.Version),
+		Cipher:    tls.CipherSuiteName(state.CipherSuite),
+		Issuer:    certificateName(leaf.Issuer),
+		Subject:   certificateName(leaf.Subject),
+		SANs:      slices.Clone(leaf.DNSNames),
+		ValidFrom: leaf.NotBefore,
+		ValidTo:   leaf.NotAfter,
+	}
+	for _, ip := range leaf.IPAddresses {
+		info.SANs = append(info.SANs, ip.String())
+	}
+
+	verifyAt := now
+	switch {
+	case now.After(leaf.NotAfter):
+		verifyAt = leaf.NotAfter.Add(-time.Minute)
+	case now.Before(leaf.NotBefore):
+		verifyAt = leaf.NotBefore.Add(time.Minute)
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range state.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err = leaf.Verify(x509.VerifyOptions{Intermediates: intermediates, CurrentTime: verifyAt})
+	info.ChainValid = err == nil
+
+	info.inspect(host, expiryDays, now)
+	return info, nil
+}
+
+// certificateName returns the common name of a certificate's issuer or subject,
+// falling back to its organisation
+func certificateName(name pkix.Name) string {
+	if name.CommonName == "" && len(name.Organization) > 0 {
+		return name.Organization[0]
+	}
+
+	return name.CommonName
+}
+
+// inspect works out the days remaining and hosts the certificate doesn't cover,
+// warning about certificates expiring within the given number of days
+func (c *certificateInfo) inspect(host string, expiryDays int, now time.Time) {
+	if !c.ValidTo.IsZero() {
+		c.DaysRemaining = int(c.ValidTo.Sub(now).Hours() / 24)
+	}
+
+	for _, variant := range hostVariants(host) {
+		if !certificateCovers(c.SANs, c.Subject, variant) {
+			c.UncoveredHosts = append(c.UncoveredHosts, variant)
+		}
+	}
+
+	switch {
+	case c.ValidTo.IsZero():
+	case now.After(c.ValidTo):
+		c.Warnings = append(c.Warnings, fmt.Sprintf("expired on %s", c.ValidTo.Format(time.DateOnly)))
+	case c.DaysRemaining < expiryDays:
+		c.Warnings = append(c.Warnings, fmt.Sprintf("expires in %d days", c.DaysRemaining))
+	}
+	if !c.ChainValid {
+		c.Warnings = append(c.Warnings, "certificate chain isn't trusted")
+	}
+	if slices.Contains(legacyTLSProtocols, c.Protocol) {
+		c.Warnings = append(c.Warnings, fmt.Sprintf("uses deprecated %s", c.Protocol))
+	}
+	for _, uncovered := range c.UncoveredHosts {
+		c.Warnings = append(c.Warnings, fmt.Sprintf("doesn't cover %s", uncovered))
+	}
+}
+
+// hostVariants returns the host along with its www (or apex) counterpart,
+// which visitors commonly type instead
+func hostVariants(host string) []string {
+	if !strings.Contains(host, ".") || net.ParseIP(host) != nil {
+		return []string{host} // localhost or an IP
+	}
+
+	if apex, ok := strings.CutPrefix(host, "www."); ok {
+		return []string{host, apex}
+	}
+
+	if registrableDomain(host) != host {
+		return []string{host} // other subdomains have no www counterpart
+	}
+
+	return []string{host, "www." + host}
+}
+
+// certificateCovers reports whether the certificate's names (SANs, falling back
+// to the subject) cover the host - wildcards match a single label
+func certificateCovers(sans []string, subject, host string) bool {
+	names := sans
+	if len(names) == 0 {
+		names = []string{subject}
+	}
+
+	host = strings.ToLower(host)
+	for _, name := range names {
+		name = strings.ToLower(name)
+		if name == host {
+			return true
+		}
+
+		if suffix, ok := strings.CutPrefix(name, "*."); ok {
+			label, rest, found := strings.Cut(host, ".")
+			if found && label != "" && rest == suffix {
+				return true
+			}
+		}
+	}
+
+	return false
+}
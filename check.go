@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"net/url"
+	"strings"
+
+	"github.com/chromedp/cdproto/network"
+)
+
+// Check defines the interface for a single audit check - adding a new check
+// only requires implementing it and adding it to the check registry
+type Check interface {
+	Name() string      // identifier used by the -checks flag
+	Important() bool   // whether check is part of the -important preset
+	OptIn() bool       // whether check only runs when named with -checks (e.g. it slows the page down)
+	Scripts() []string // JS scripts to inject before navigating to the page
+	// starts tracking the page load (e.g. through CDP), before navigating to it
+	Prepare(ctx context.Context, page *auditPage) error
+	Run(ctx context.Context, page *auditPage) (any, error)
+	Columns() []string                     // output column headers
+	Values(result any) []string            // output column values, matching Columns
+	Issues(result any) int                 // number of issues found in a result
+	Decode(data []byte) (any, error)       // decodes a JSON encoded result
+	Sampled() bool                         // whether the check is re-run on every -runs sample
+	Timings(result any) map[string]float64 // timing metrics measured in a result, by name
+}
+
+// auditPage holds the state of a loaded page that checks are evaluated against
+type auditPage struct {
+	website       *Website
+	url           string
+	device        string            // device name, only set when auditing on multiple devices
+	response      *network.Response // main document response
+	requests      *networkRecorder  // requests made by the page, recorded since navigation
+	important     bool
+	screenshotDir string
+	certExpiry    int            // certificates expiring sooner are warned about
+	results       map[string]any // results of checks run so far, by check name
+	tracking      map[string]any // state of page load tracking started by checks, by check name
+}
+
+// name returns a name identifying the page, made from the domain, the path
+// for inner pages, and the device if set - inner pages get a short hash of
+// their path and query too, since pages can differ only by their query, or
+// by characters lost when the name is sanitised
+func (p *auditPage) name() string {
+	name := p.website.domain
+
+	parsed, err := url.Parse(p.url)
+	if err == nil && (strings.Trim(parsed.Path, "/") != "" || parsed.RawQuery != "") {
+		if path := strings.Trim(parsed.Path, "/"); path != "" {
+			name += "_" + path
+		}
+
+		hash := fnv.New32a()
+		hash.Write([]byte(crawlKey(p.url)))
+		name += fmt.Sprintf("_%08x", hash.Sum32())
+	}
+
+	if p.device != "" {
+		name += "_" + p.device
+	}
+
+	return name
+}
+
+// auditCheck is a generic Check implementation, typed by the check's result
+// - it satisfies the Check interface
+type auditCheck[T any] struct {
+	name      string
+	important bool
+	optIn     bool
+	scripts   []string
+	columns   []string
+	prepare   func(ctx context.Context, page *auditPage) error // optional, for checks tracking the page load
+	run       func(ctx context.Context, page *auditPage) (T, error)
+	values    func(result T) []string
+	issues    func(result T) int                // optional, for checks that report issues
+	timings   func(result T) map[string]float64 // optional, for checks measuring timings
+}
+
+// Name returns the check name
+func (c *auditCheck[T]) Name() string {
+	return c.name
+}
+
+// Important reports whether the check is part of the important preset
+func (c *auditCheck[T]) Important() bool {
+	return c.important
+}
+
+// OptIn reports whether the check is left out unless named with -checks
+func (c *auditCheck[T]) OptIn() bool {
+	return c.optIn
+}
+
+// Scripts returns JS scripts the check needs injected into the page
+func (c *auditCheck[T]) Scripts() []string {
+	return c.scripts
+}
+
+// Prepare starts any tracking the check needs while the page loads
+func (c *auditCheck[T]) Prepare(ctx context.Context, page *auditPage) error {
+	if c.prepare == nil {
+		return nil
+	}
+
+	return c.prepare(ctx, page)
+}
+
+// Run evaluates the check on the loaded page
+func (c *auditCheck[T]) Run(ctx context.Context, page *auditPage) (any, error) {
+	return c.run(ctx, page)
+}
+
+// Columns returns the check's output column headers
+func (c *auditCheck[T]) Columns() []string {
+	return c.columns
+}
+
+// Values formats the check result into output column values
+// (a missing result is left empty)
+func (c *auditCheck[T]) Values(result any) []string {
+	typed, ok := result.(T)
+	if !ok {
+		return make([]string, len(c.columns))
+	}
+
+	return c.values(typed)
+}
+
+// Issues counts the issues found in the check result
+func (c *auditCheck[T]) Issues(result any) int {
+	typed, ok := result.(T)
+	if !ok || c.issues == nil {
+		return 0
+	}
+
+	return c.issues(typed)
+}
+
+// Sampled reports whether the check measures timings, so is re-run on every sample
+func (c *auditCheck[T]) Sampled() bool {
+	return c.timings != nil
+}
+
+// Timings returns the timing metrics measured in the check result
+// (unmeasured metrics are left out)
+func (c *auditCheck[T]) Timings(result any) map[string]float64 {
+	typed, ok := result.(T)
+	if !ok || c.timings == nil {
+		return nil
+	}
+
+	return c.timings(typed)
+}
+
+// Decode decodes a JSON encoded check result into its type
+// (a null result is decoded as missing)
+func (c *auditCheck[T]) Decode(data []byte) (any, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+
+	var result T
+	err := json.Unmarshal(data, &result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s result: %w", c.name, err)
+	}
+
+	return result, nil
+}
+
+// checkRegistry lists all available checks, in the order they are run and output
+// (checks may depend on results of checks listed before them)
+var checkRegistry = []Check{
+	securityCheck,
+	tlsCheck,
+	mixedContentCheck,
+	lcpCheck,
+	vitalsCheck,
+	longTasksCheck,
+	weightCheck,
+	blockingCheck,
+	imagesCheck,
+	coverageCheck,
+	thirdPartyCheck,
+	consoleCheck,
+	requestCheck,
+	headersCheck,
+	cachingCheck,
+	mobileCheck,
+	formCheck,
+	techCheck,
+	screenshotCheck,
+}
+
+// lookupCheck finds a registered check by name
+func lookupCheck(name string) (Check, bool) {
+	for _, check := range checkRegistry {
+		if check.Name() == name {
+			return check, true
+		}
+	}
+
+	return nil, false
+}
+
+// checkNames returns the comma-separated names of all registered checks
+func checkNames() string {
+	names := make([]string, 0, len(checkRegistry))
+	for _, check := range checkRegistry {
+		names = append(names, check.Name())
+	}
+
+	return strings.Join(names, ",")
+}
+
+// parseChecks returns the registered checks matching the provided comma-separated
+// names, in registry order
+func parseChecks(checksStr string) ([]Check, error) {
+	requested := map[string]bool{}
+	for name := range strings.SplitSeq(checksStr, ",") {
+		name = strings.TrimSpace(name)
+		if _, ok := lookupCheck(name); !ok {
+			return nil, fmt.Errorf("unknown check: %s", name)
+		}
+
+		requested[name] = true
+	}
+
+	checks := []Check{}
+	for _, check := range checkRegistry {
+		if requested[check.Name()] {
+			checks = append(checks, check)
+		}
+	}
+
+	return checks, nil
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// Checkpoint persists each completed audit result to a JSON Lines state file,
+// so an interrupted run can be resumed without re-auditing finished sites
+type Checkpoint struct {
+	stateFile string
+	completed []auditResult // results loaded from a previous run
+	mu        sync.Mutex
+}
+
+// NewCheckpoint creates a new Checkpoint instance - when resuming, results of
+// the previous run are loaded from the state file, otherwise it's cleared
+func NewCheckpoint(stateFile string, resume bool) (*Checkpoint, error) {
+	if stateFile == "" {
+		return nil, fmt.Errorf("checkpoint path cannot be empty")
+	}
+
+	checkpoint := Checkpoint{stateFile: stateFile}
+
+	if resume {
+		err := checkpoint.load()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load checkpoint: %w", err)
+		}
+
+		return &checkpoint, nil
+	}
+
+	err := createOutputFile(stateFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed checkpoint file validation/creation: %w", err)
+	}
+
+	return &checkpoint, nil
+}
+
+// Completed returns the results loaded from a previous run
+func (c *Checkpoint) Completed() []auditResult {
+	return c.completed
+}
+
+// checkpointRecord mirrors the JSON representation of an audit result, keeping
+// check results raw so they can be decoded by their checks
+type checkpointRecord struct {
+	Website   string `json:"website"`
+	Device    string `json:"device"`
+	Throttle  string `json:"throttle"`
+	Runs      int    `json:"runs"`
+	MultiPage bool   `json:"multiPage"`
+	HAR       string `json:"har"`
+	Pages     []struct {
+		URL         string                     `json:"url"`
+		Attempts    int                        `json:"attempts"`
+		Failure     failureCategory            `json:"failure"`
+		HTTPStatus  int64                      `json:"httpStatus"`
+		Checks      map[string]json.RawMessage `json:"checks"`
+		CheckErrors map[string]string          `json:"checkErrors"`
+		AuditErrors []string                   `json:"auditErrors"`
+		Timings     map[string]timingStats     `json:"timings"`
+	} `json:"pages"`
+	Settings auditSettings `json:"settings"`
+}
+
+// checkpointEntry is an audit result as written to the state file - its JSON
+// representation, along with the settings it was audited with
+type checkpointEntry struct {
+	jsonResult
+	Settings auditSettings `json:"settings"`
+}
+
+// load reads results of a previous run from the state file
+// (a missing file means there's nothing to resume)
+func (c *Checkpoint) load() error {
+	file, err := os.Open(c.stateFile)
+	if os.IsNotExist(err) {
+		return createOutputFile(c.stateFile)
+	} else if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024) // results can be large
+
+	for scanner.Scan() {
+		var record checkpointRecord
+		err := json.Unmarshal(scanner.Bytes(), &record)
+		if err != nil {
+			// likely a partially written line, from the run being interrupted
+			fmt.Printf("⚠️ skipping unreadable checkpoint entry: %v\n", err)
+			continue
+		}
+
+		result, err := c.decodeRecord(record)
+		if err != nil {
+			return err
+		}
+
+		c.completed = append(c.completed, result)
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	return nil
+}
+
+// decodeRecord converts a checkpoint record back into an audit result
+func (c *Checkpoint) decodeRecord(record checkpointRecord) (auditResult, error) {
+	result := auditResult{
+		website:   record.Website,
+		device:    record.Device,
+		throttle:  record.Throttle,
+		runs:      record.Runs,
+		multiPage: record.MultiPage,
+		harFile:   record.HAR,
+		settings:  record.Settings,
+	}
+
+	// all pages hold results for the same enabled checks, kept in registry order
+	if len(record.Pages) > 0 {
+		for _, check := range checkRegistry {
+			if _, ok := record.Pages[0].Checks[check.Name()]; ok {
+				result.checks = append(result.checks, check)
+			}
+		}
+	}
+
+	for _, recordPage := range record.Pages {
+		page := pageResult{
+			url:        recordPage.URL,
+			attempts:   recordPage.Attempts,
+			failure:    recordPage.Failure,
+			httpStatus: recordPage.HTTPStatus,
+			results:    map[string]any{},
+			checkErrs:  recordPage.CheckErrors,
+			auditErrs:  recordPage.AuditErrors,
+			timings:    recordPage.Timings,
+		}
+		if page.checkErrs == nil {
+			page.checkErrs = map[string]string{}
+		}
+
+		for name, data := range recordPage.Checks {
+			check, ok := lookupCheck(name)
+			if !ok {
+				return auditResult{}, fmt.Errorf("unknown check %s in %s result", name, record.Website)
+			}
+
+			res, err := check.Decode(data)
+			if err != nil {
+				return auditResult{}, fmt.Errorf("failed to decode %s result: %w", record.Website, err)
+			}
+
+			if res != nil {
+				page.results[name] = res
+			}
+		}
+
+		result.pages = append(result.pages, page)
+	}
+
+	if len(result.pages) == 0 {
+		return auditResult{}, fmt.Errorf("no pages in %s result", record.Website)
+	}
+
+	return result, nil
+}
+
+// WriteResult appends a completed result to the state file
+func (c *Checkpoint) WriteResult(result auditResult) error {
+	if c == nil || c.stateFile == "" {
+		return fmt.Errorf("nil checkpoint")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	file, err := os.OpenFile(c.stateFile, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	err = json.NewEncoder(file).Encode(checkpointEntry{jsonResult: newJSONResult(result), Settings: result.settings})
+	if err != nil {
+		return fmt.Errorf("failed to write to file: %w", err)
+	}
+
+	// make sure the result survives a crash
+	return file.Sync()
+}
+
+// Remove deletes the state file, once the run has completed
+func (c *Checkpoint) Remove() error {
+	err := os.Remove(c.stateFile)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove checkpoint: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,300 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/chromedp/cdproto/css"
+	"github.com/chromedp/cdproto/dom"
+	"github.com/chromedp/cdproto/profiler"
+	"github.com/chromedp/chromedp"
+)
+
+const (
+	coverageName     = "coverage" // check name, also keying its tracking state
+	minUnusedBytes   = 20 * 1024  // files with less unused code aren't worth reporting as issues (as in Lighthouse)
+	maxCoverageFiles = 10         // number of files listed in output
+)
+
+// fileCoverage holds how much of a script or stylesheet was used while
+// loading the page - sizes are of the uncompressed source
+type fileCoverage struct {
+	URL         string `json:"url"` // "inline" for inline scripts and styles
+	Type        string `json:"type"`
+	TotalBytes  int64  `json:"totalBytes"`
+	UnusedBytes int64  `json:"unusedBytes"`
+}
+
+// String formats the file coverage for output
+func (f fileCoverage) String() string {
+	return fmt.Sprintf("%s (%s) - %s", f.URL, f.Type, formatUnused(f.UnusedBytes, f.TotalBytes))
+}
+
+// codeCoverage summarises unused JS and CSS on the page
+type codeCoverage struct {
+	Files                 []fileCoverage `json:"files"` // most unused bytes first
+	ScriptBytes           int64          `json:"scriptBytes"`
+	UnusedScriptBytes     int64          `json:"unusedScriptBytes"`
+	StylesheetBytes       int64          `json:"stylesheetBytes"`
+	UnusedStylesheetBytes int64          `json:"unusedStylesheetBytes"`
+}
+
+// newCodeCoverage summarises file coverage, totalling it by type
+func newCodeCoverage(files []fileCoverage) codeCoverage {
+	coverage := codeCoverage{Files: files}
+	for _, file := range files {
+		switch file.Type {
+		case "script":
+			coverage.ScriptBytes += file.TotalBytes
+			coverage.UnusedScriptBytes += file.UnusedBytes
+		case "stylesheet":
+			coverage.StylesheetBytes += file.TotalBytes
+			coverage.UnusedStylesheetBytes += file.UnusedBytes
+		}
+	}
+
+	slices.SortStableFunc(coverage.Files, func(a, b fileCoverage) int {
+		return cmp.Or(cmp.Compare(b.UnusedBytes, a.UnusedBytes), cmp.Compare(a.URL, b.URL))
+	})
+
+	return coverage
+}
+
+// wastefulFiles counts files with enough unused code to be worth trimming
+func (c codeCoverage) wastefulFiles() int {
+	wasteful := 0
+	for _, file := range c.Files {
+		if file.UnusedBytes >= minUnusedBytes {
+			wasteful++
+		}
+	}
+
+	return wasteful
+}
+
+// formatUnused formats unused bytes out of a total, with the percentage
+func formatUnused(unused, total int64) string {
+	if total == 0 {
+		return "0 B"
+	}
+
+	return fmt.Sprintf(
+		"%s of %s unused (%.0f%%)",
+		formatBytes(unused), formatBytes(total), float64(unused)/float64(total)*100,
+	)
+}
+
+// styleSheetRecorder records stylesheets added to the page, from CDP CSS
+// events, so rule usage can be matched to their URLs and sizes
+type styleSheetRecorder struct {
+	mu     sync.Mutex
+	sheets map[css.StyleSheetID]*css.StyleSheetHeader
+}
+
+// startCoverage starts JS precise coverage and CSS rule usage tracking, along
+// with recording stylesheets, before the page is loaded
+func startCoverage(ctx context.Context, page *auditPage) error {
+	err := profiler.Enable().Do(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to enable profiler domain: %w", err)
+	}
+
+	// detailed (block level) coverage marks unused code within called functions too
+	_, err = profiler.StartPreciseCoverage().WithDetailed(true).Do(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start JS coverage: %w", err)
+	}
+
+	recorder := &styleSheetRecorder{sheets: map[css.StyleSheetID]*css.StyleSheetHeader{}}
+	chromedp.ListenTarget(ctx, func(ev any) {
+		if ev, ok := ev.(*css.EventStyleSheetAdded); ok {
+			recorder.mu.Lock()
+			recorder.sheets[ev.Header.StyleSheetID] = ev.Header
+			recorder.mu.Unlock()
+		}
+	})
+	page.tracking[coverageName] = recorder
+
+	// CSS domain needs the DOM domain enabled
+	err = dom.Enable().Do(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to enable DOM domain: %w", err)
+	}
+
+	err = css.Enable().Do(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to enable CSS domain: %w", err)
+	}
+
+	err = css.StartRuleUsageTracking().Do(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start CSS rule usage tracking: %w", err)
+	}
+
+	return nil
+}
+
+// takeCoverage stops tracking started by startCoverage, and collects the
+// coverage of each script and stylesheet
+func takeCoverage(ctx context.Context, page *auditPage) (codeCoverage, error) {
+	scripts, _, err := profiler.TakePreciseCoverage().Do(ctx)
+	if err != nil {
+		return codeCoverage{}, fmt.Errorf("failed to take JS coverage: %w", err)
+	}
+
+	err = profiler.StopPreciseCoverage().Do(ctx)
+	if err != nil {
+		return codeCoverage{}, fmt.Errorf("failed to stop JS coverage: %w", err)
+	}
+
+	rules, err := css.StopRuleUsageTracking().Do(ctx)
+	if err != nil {
+		return codeCoverage{}, fmt.Errorf("failed to stop CSS rule usage tracking: %w", err)
+	}
+
+	recorder, ok := page.tracking[coverageName].(*styleSheetRecorder)
+	if !ok {
+		return codeCoverage{}, fmt.Errorf("stylesheets weren't recorded")
+	}
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	documentURL := page.url
+	if page.response != nil {
+		documentURL = page.response.URL // after any redirects
+	}
+
+	files := append(scriptCoverage(scripts, documentURL), styleSheetCoverage(rules, recorder.sheets)...)
+	return newCodeCoverage(files), nil
+}
+
+// scriptCoverage works out unused bytes of each script from its block coverage -
+// inline scripts (named after the document) are grouped together
+func scriptCoverage(scripts []*profiler.ScriptCoverage, documentURL string) []fileCoverage {
+	byURL := map[string]*fileCoverage{}
+	files := []*fileCoverage{}
+
+	for _, script := range scripts {
+		if !strings.HasPrefix(script.URL, "http") {
+			continue // eval'd code, extensions and internal scripts
+		}
+
+		total, unused := unusedScriptBytes(script.Functions)
+		if total == 0 {
+			continue
+		}
+
+		name := script.URL
+		if name == documentURL {
+			name = "inline"
+		}
+
+		file, ok := byURL[name]
+		if !ok {
+			file = &fileCoverage{URL: name, Type: "script"}
+			byURL[name] = file
+			files = append(files, file)
+		}
+
+		file.TotalBytes += total
+		file.UnusedBytes += unused
+	}
+
+	coverage := []fileCoverage{}
+	for _, file := range files {
+		coverage = append(coverage, *file)
+	}
+
+	return coverage
+}
+
+// unusedScriptBytes returns the size of a script and its bytes that never ran -
+// ranges are nested (the outermost being the whole script), with inner ones
+// overriding the execution count of the ranges containing them
+func unusedScriptBytes(functions []*profiler.FunctionCoverage) (int64, int64) {
+	ranges := []*profiler.CoverageRange{}
+	total := int64(0)
+	for _, function := range functions {
+		for _, r := range function.Ranges {
+			ranges = append(ranges, r)
+			total = max(total, r.EndOffset)
+		}
+	}
+
+	// outer ranges first, so inner ones are applied over them
+	slices.SortStableFunc(ranges, func(a, b *profiler.CoverageRange) int {
+		return cmp.Or(cmp.Compare(a.StartOffset, b.StartOffset), cmp.Compare(b.EndOffset, a.EndOffset))
+	})
+
+	used := make([]bool, total)
+	for _, r := range ranges {
+		for i := max(r.StartOffset, 0); i < min(r.EndOffset, total); i++ {
+			used[i] = r.Count > 0
+		}
+	}
+
+	unused := int64(0)
+	for _, isUsed := range used {
+		if !isUsed {
+			unused++
+		}
+	}
+
+	return total, unused
+}
+
+// styleSheetCoverage works out unused bytes of each stylesheet, as the bytes
+// outside its used rules - inline styles are grouped together
+func styleSheetCoverage(rules []*css.RuleUsage, sheets map[css.StyleSheetID]*css.StyleSheetHeader) []fileCoverage {
+	usedBytes := map[css.StyleSheetID]int64{}
+	for _, rule := range rules {
+		if rule.Used {
+			usedBytes[rule.StyleSheetID] += int64(rule.EndOffset - rule.StartOffset)
+		}
+	}
+
+	byURL := map[string]*fileCoverage{}
+	files := []*fileCoverage{}
+
+	// sheets are visited in a stable order, so output is deterministic
+	ids := []css.StyleSheetID{}
+	for id := range sheets {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	for _, id := range ids {
+		sheet := sheets[id]
+		if sheet.Origin != css.StyleSheetOriginRegular || sheet.Length == 0 || sheet.SourceURL == "" {
+			continue // browser, injected and constructed stylesheets
+		}
+
+		name := sheet.SourceURL
+		if sheet.IsInline {
+			name = "inline"
+		}
+
+		file, ok := byURL[name]
+		if !ok {
+			file = &fileCoverage{URL: name, Type: "stylesheet"}
+			byURL[name] = file
+			files = append(files, file)
+		}
+
+		total := int64(sheet.Length)
+		file.TotalBytes += total
+		file.UnusedBytes += max(0, total-usedBytes[id])
+	}
+
+	coverage := []fileCoverage{}
+	for _, file := range files {
+		coverage = append(coverage, *file)
+	}
+
+	return coverage
+}
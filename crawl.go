@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/chromedp/chromedp"
+)
+
+// discoverLinks collects the internal links on the currently loaded page
+// that are worth crawling
+func (a *Audit) discoverLinks(ctx context.Context) ([]string, error) {
+	var location string
+	var hrefs []string
+
+	err := chromedp.Run(ctx,
+		chromedp.Location(&location),
+		chromedp.Evaluate(linksScript, &hrefs),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover links: %w", err)
+	}
+
+	return filterInternalLinks(location, hrefs), nil
+}
+
+// filterInternalLinks keeps links pointing to pages on the same host as the
+// page they were found on, normalised and without duplicates
+func filterInternalLinks(pageURL string, hrefs []string) []string {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil
+	}
+
+	links := []string{}
+	seen := map[string]bool{}
+
+	for _, href := range hrefs {
+		link, err := url.Parse(href)
+		if err != nil {
+			continue
+		}
+
+		if link.Scheme != "http" && link.Scheme != "https" {
+			continue
+		}
+
+		if !strings.EqualFold(link.Host, base.Host) {
+			continue
+		}
+
+		// skip links with query strings, since they can point to endless variations
+		// of the same page, or trigger actions (e.g. "?add-to-cart=")
+		if link.RawQuery != "" {
+			continue
+		}
+
+		if slices.Contains(ignoredCrawlExtensions, strings.ToLower(path.Ext(link.Path))) {
+			continue
+		}
+
+		link.Fragment = ""
+		if link.Path == "" {
+			link.Path = "/"
+		}
+
+		key := crawlKey(link.String())
+		if seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		links = append(links, link.String())
+	}
+
+	return links
+}
+
+// crawlKey returns a key identifying a page regardless of its scheme
+// or trailing slash, used to avoid auditing the same page twice
+// (the query is kept, since listed entry pages can differ only by it)
+func crawlKey(pageURL string) string {
+	parsed, err := url.Parse(pageURL)
+	if err != nil {
+		return pageURL
+	}
+
+	key := strings.ToLower(parsed.Host) + "/" + strings.Trim(parsed.Path, "/")
+	if parsed.RawQuery != "" {
+		key += "?" + parsed.RawQuery
+	}
+
+	return key
+}
+
+// file extensions of linked resources that aren't pages
+var ignoredCrawlExtensions = []string{
+	".pdf", ".jpg", ".jpeg", ".png", ".gif", ".webp", ".svg", ".avif",
+	".zip", ".rar", ".doc", ".docx", ".xls", ".xlsx", ".ppt", ".pptx",
+	".mp3", ".mp4", ".mov", ".avi", ".webm", ".xml", ".txt", ".csv",
+}
+
+// pageIssues returns the number of issues checks found on the given page
+func (r auditResult) pageIssues(page pageResult) int {
+	issues := len(page.auditErrs)
+	for _, check := range r.checks {
+		issues += check.Issues(page.results[check.Name()])
+	}
+
+	return issues
+}
+
+// totalIssues returns the number of issues found across all audited pages
+func (r auditResult) totalIssues() int {
+	total := 0
+	for _, page := range r.pages {
+		total += r.pageIssues(page)
+	}
+
+	return total
+}
+
+// worstPage returns the audited page with the most issues
+func (r auditResult) worstPage() (pageResult, int) {
+	var worst pageResult
+	worstIssues := -1
+
+	for _, page := range r.pages {
+		issues := r.pageIssues(page)
+		if issues > worstIssues {
+			worst, worstIssues = page, issues
+		}
+	}
+
+	return worst, worstIssues
+}
@@ -4,18 +4,20 @@ import (
 	"encoding/csv"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 )
 
-// CSVSink handles writing audit results to a CSV file -
-// it represents the destination end of the audit data flow
+// CSVSink handles writing audit results to a CSV file
+// - it satisfies the sink interface
 type CSVSink struct {
+	name       string
 	outputFile string
 }
 
 // NewCSVSink creates a new CSVSink instance
 func NewCSVSink(outputFile string) (*CSVSink, error) {
-	newSink := CSVSink{outputFile}
+	newSink := CSVSink{name: "csv sink", outputFile: outputFile}
 	err := newSink.validateAndCreateOutputFile()
 	if err != nil {
 		return nil, fmt.Errorf("failed csv output file validation/creation: %w", err)
@@ -24,112 +26,191 @@ func NewCSVSink(outputFile string) (*CSVSink, error) {
 	return &newSink, nil
 }
 
+// Name returns the sink name
+func (s *CSVSink) Name() string {
+	return s.name
+}
+
 // validateAndCreateOutputFile ensures the output directory exists and is writable
 func (s *CSVSink) validateAndCreateOutputFile() error {
-	if s.outputFile == "" {
-		return fmt.Errorf("output path cannot be empty")
-	}
-
-	// create the output file
-	// this validates both directory existence and write permissions
-	file, err := os.Create(s.outputFile)
-	if err != nil {
-		return fmt.Errorf("cannot create output file %s: %w", s.outputFile, err)
-	}
-	file.Close()
-
-	return nil
+	return createOutputFile(s.outputFile)
 }
 
-// WriteResults writes the results to the output CSV
+// WriteResults writes the results to the output CSV - if multiple pages were
+// audited per site, per page results are written to a separate "_pages" CSV next to it
 func (s *CSVSink) WriteResults(results []auditResult) error {
 	if s == nil || s.outputFile == "" {
 		return fmt.Errorf("nil csv sink")
 	}
 
-	outFile, err := os.Create(s.outputFile)
-	if err != nil {
-		return fmt.Errorf("failed to open file: %w", err)
-	}
-	defer outFile.Close()
-
-	writer := csv.NewWriter(outFile)
-	defer writer.Flush()
+	multiPage := results[0].multiPage
+	timings := sampledMetrics(results)
+	byDevice := multipleDevices(results)
+	throttled := results[0].throttle != "none"
+	withHAR := exportedHAR(results)
 
 	headers := []string{"Website"}
-	enabledHeaders, _ := s.getEnabledChecks(results[0].checks)
-	headers = append(headers, enabledHeaders...)
-	headers = append(headers, "Audit Errors")
-
-	err = writer.Write(headers)
-	if err != nil {
-		return fmt.Errorf("failed to write to file: %w", err)
+	if byDevice {
+		headers = append(headers, "Device")
+	}
+	if throttled {
+		headers = append(headers, "Throttling")
+	}
+	for _, check := range results[0].checks {
+		headers = append(headers, check.Columns()...)
+	}
+	headers = append(headers, "Audit Errors", "Attempts", "Failure", "HTTP Status")
+	for _, metric := range timings {
+		headers = append(headers, timingLabels[metric]+" Samples")
+	}
+	if multiPage {
+		headers = append(headers, "Pages Audited", "Total Issues", "Worst Page")
+	}
+	for _, budget := range results[0].budgets {
+		headers = append(headers, "Budget: "+budget.Budget)
+	}
+	if len(results[0].budgets) > 0 {
+		headers = append(headers, "Budget Verdict")
+	}
+	if withHAR {
+		headers = append(headers, "HAR")
 	}
 
+	rows := [][]string{headers}
 	for _, res := range results {
+		// site row holds the entry page results, and a summary of all audited pages
 		row := []string{res.website}
-		_, enabledValues := s.getEnabledChecks(res.checks)
-		row = append(row, enabledValues...)
-		row = append(row, strings.Join(res.auditErrs, ";\n"))
-
-		err := writer.Write(row)
-		if err != nil {
-			return fmt.Errorf("failed to write to file: %w", err)
+		if byDevice {
+			row = append(row, res.device)
+		}
+		if throttled {
+			row = append(row, res.throttle)
+		}
+		row = append(row, s.pageValues(res, res.pages[0])...)
+		row = append(row, s.timingValues(res.pages[0], timings)...)
+		if multiPage {
+			worst, _ := res.worstPage()
+			row = append(row, fmt.Sprint(len(res.pages)), fmt.Sprint(res.totalIssues()), worst.url)
+		}
+		for _, budget := range res.budgets {
+			row = append(row, budget.formatValue())
+		}
+		if len(res.budgets) > 0 {
+			row = append(row, res.budgetVerdict())
+		}
+		if withHAR {
+			row = append(row, res.harFile)
 		}
-	}
-
-	writer.Flush()
-	return nil
-}
 
-// getEnabledChecks returns headers and values for enabled checks
-func (s *CSVSink) getEnabledChecks(checks auditChecks) (headers []string, values []string) {
-	if checks.secure.enabled {
-		headers = append(headers, "Secure")
-		values = append(values, s.boolToEmoji(checks.secure.result))
+		rows = append(rows, row)
 	}
-	if checks.lcp.enabled {
-		headers = append(headers, "LCP (ms)")
-		values = append(values, fmt.Sprint(checks.lcp.result))
+
+	err := s.writeFile(s.outputFile, rows)
+	if err != nil {
+		return err
 	}
-	if checks.consoleErrs.enabled {
-		headers = append(headers, "Console Errors")
-		values = append(values, strings.Join(checks.consoleErrs.result, ";\n"))
+
+	if !multiPage {
+		return nil
 	}
-	if checks.requestErrs.enabled {
-		headers = append(headers, "Request Errors")
-		values = append(values, strings.Join(checks.requestErrs.result, ";\n"))
+
+	return s.writeFile(s.pagesFile(), s.pageRows(results, byDevice, timings))
+}
+
+// pageRows returns the per page results, with a row for each audited page
+func (s *CSVSink) pageRows(results []auditResult, byDevice bool, timings []string) [][]string {
+	headers := []string{"Website", "Page"}
+	if byDevice {
+		headers = append(headers, "Device")
 	}
-	if checks.missingHeaders.enabled {
-		headers = append(headers, "Missing Headers")
-		values = append(values, strings.Join(checks.missingHeaders.result, ";\n"))
+	for _, check := range results[0].checks {
+		headers = append(headers, check.Columns()...)
 	}
-	if checks.responsiveIssues.enabled {
-		headers = append(headers, "Responsive Issues")
-		values = append(values, strings.Join(checks.responsiveIssues.result, ";\n"))
+	headers = append(headers, "Audit Errors", "Attempts", "Failure", "HTTP Status", "Issues")
+	for _, metric := range timings {
+		headers = append(headers, timingLabels[metric]+" Samples")
 	}
-	if checks.formIssues.enabled {
-		headers = append(headers, "Form Issues")
-		values = append(values, strings.Join(checks.formIssues.result, ";\n"))
+
+	rows := [][]string{headers}
+	for _, res := range results {
+		for _, page := range res.pages {
+			row := []string{res.website, page.url}
+			if byDevice {
+				row = append(row, res.device)
+			}
+			row = append(row, s.pageValues(res, page)...)
+			row = append(row, fmt.Sprint(res.pageIssues(page)))
+			row = append(row, s.timingValues(page, timings)...)
+
+			rows = append(rows, row)
+		}
 	}
-	if checks.techStack.enabled {
-		headers = append(headers, "Detected Tech")
-		values = append(values, strings.Join(checks.techStack.result, ";\n"))
+
+	return rows
+}
+
+// pageValues returns the check values, audit errors, load attempts and failure
+// details of a single page
+func (s *CSVSink) pageValues(res auditResult, page pageResult) []string {
+	values := []string{}
+	for _, check := range res.checks {
+		values = append(values, check.Values(page.results[check.Name()])...)
+	}
+
+	return append(
+		values,
+		strings.Join(page.errors(res.checks), ";\n"),
+		fmt.Sprint(page.attempts),
+		string(page.failure),
+		s.statusValue(page.httpStatus),
+	)
+}
+
+// timingValues returns the page's stats of the given sampled timing metrics
+func (s *CSVSink) timingValues(page pageResult, timings []string) []string {
+	values := []string{}
+	for _, metric := range timings {
+		stats, ok := page.timings[metric]
+		if !ok {
+			values = append(values, "")
+			continue
+		}
+
+		values = append(values, stats.String())
 	}
-	if checks.screenshot.enabled {
-		headers = append(headers, "Screenshot")
-		values = append(values, s.boolToEmoji(checks.screenshot.result))
+
+	return values
+}
+
+// statusValue formats an HTTP status, leaving it empty if no response was received
+func (s *CSVSink) statusValue(status int64) string {
+	if status == 0 {
+		return ""
 	}
 
-	return headers, values
+	return fmt.Sprint(status)
 }
 
-// boolToEmoji takes in a boolean and returns corresponding
-// emoji to visual inspection
-func (s *CSVSink) boolToEmoji(ok bool) string {
-	if !ok {
-		return "❌"
+// pagesFile returns the path of the per page results CSV
+func (s *CSVSink) pagesFile() string {
+	ext := filepath.Ext(s.outputFile)
+	return strings.TrimSuffix(s.outputFile, ext) + "_pages" + ext
+}
+
+// writeFile writes the given rows to a CSV file
+func (s *CSVSink) writeFile(path string, rows [][]string) error {
+	outFile, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	defer outFile.Close()
+
+	writer := csv.NewWriter(outFile)
+
+	err = writer.WriteAll(rows)
+	if err != nil {
+		return fmt.Errorf("failed to write to file: %w", err)
 	}
 
-	return "✅"
+	return nil
 }
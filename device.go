@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/chromedp/chromedp/device"
+)
+
+// deviceProfile is a device emulated while auditing pages
+// - it satisfies the chromedp.Device interface
+type deviceProfile struct {
+	name string
+	info device.Info
+}
+
+// Device returns the device info used for emulation
+func (d deviceProfile) Device() device.Info {
+	return d.info
+}
+
+// user agent used when emulating desktop viewports
+const desktopUserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/137.0.0.0 Safari/537.36"
+
+// devicePresets maps device names accepted by the -devices flag to their info
+var devicePresets = map[string]device.Info{
+	"iphone13":     device.IPhone13.Device(),
+	"iphone12":     device.IPhone12.Device(),
+	"pixel5":       device.Pixel5.Device(),
+	"pixel7":       pixel7,
+	"galaxys9":     device.GalaxyS9.Device(),
+	"ipad":         device.IPad.Device(),
+	"ipadpro":      device.IPadPro.Device(),
+	"desktop-1280": desktopInfo("desktop-1280", 1280, 800),
+	"desktop-1440": desktopInfo("desktop-1440", 1440, 900),
+	"desktop-1920": desktopInfo("desktop-1920", 1920, 1080),
+}
+
+// Pixel 7 isn't one of chromedp's predefined devices
+var pixel7 = device.Info{
+	Name:      "Pixel 7",
+	UserAgent: "Mozilla/5.0 (Linux; Android 13; Pixel 7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/137.0.0.0 Mobile Safari/537.36",
+	Width:     412,
+	Height:    915,
+	Scale:     2.625,
+	Mobile:    true,
+	Touch:     true,
+}
+
+// desktopInfo returns the device info of a desktop browser with the given viewport
+func desktopInfo(name string, width, height int64) device.Info {
+	return device.Info{
+		Name:      name,
+		UserAgent: desktopUserAgent,
+		Width:     width,
+		Height:    height,
+		Scale:     1,
+	}
+}
+
+// parseDevices returns the device profiles matching the provided comma-separated
+// names - besides presets, custom desktop viewports can be given as "WIDTHxHEIGHT"
+func parseDevices(devicesStr string) ([]deviceProfile, error) {
+	devices := []deviceProfile{}
+	seen := map[string]bool{}
+
+	for name := range strings.SplitSeq(devicesStr, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+
+		info, ok := devicePresets[name]
+		if !ok {
+			var err error
+			info, err = parseViewport(name)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		seen[name] = true
+		devices = append(devices, deviceProfile{name: name, info: info})
+	}
+
+	if len(devices) == 0 {
+		return nil, fmt.Errorf("no devices specified")
+	}
+
+	return devices, nil
+}
+
+// parseViewport parses a custom "WIDTHxHEIGHT" desktop viewport
+func parseViewport(name string) (device.Info, error) {
+	widthStr, heightStr, ok := strings.Cut(name, "x")
+	if !ok {
+		return device.Info{}, fmt.Errorf("unknown device: %s", name)
+	}
+
+	width, err := strconv.ParseInt(widthStr, 10, 64)
+	if err != nil || width <= 0 {
+		return device.Info{}, fmt.Errorf("invalid viewport width: %s", name)
+	}
+
+	height, err := strconv.ParseInt(heightStr, 10, 64)
+	if err != nil || height <= 0 {
+		return device.Info{}, fmt.Errorf("invalid viewport height: %s", name)
+	}
+
+	return desktopInfo(name, width, height), nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// failureCategory classifies why a page couldn't be (fully) audited
+type failureCategory string
+
+const (
+	failureNone        failureCategory = ""
+	failureDNS         failureCategory = "dns"
+	failureConnRefused failureCategory = "connection_refused"
+	failureTLS         failureCategory = "tls"
+	failureTimeout     failureCategory = "timeout"
+	failureHTTPStatus  failureCategory = "http_status"
+	failureNetwork     failureCategory = "network" // other connection errors (e.g. resets)
+	failureBrowser     failureCategory = "browser" // browser or tab crashes, and other internal errors
+	failureCheckScript failureCategory = "check_script"
+)
+
+// browser network errors, by the failure category they belong to
+var netErrorCategories = map[failureCategory][]string{
+	failureDNS:         {"net::ERR_NAME_NOT_RESOLVED", "net::ERR_NAME_RESOLUTION_FAILED"},
+	failureConnRefused: {"net::ERR_CONNECTION_REFUSED"},
+	failureTLS:         {"net::ERR_CERT_", "net::ERR_SSL_", "net::ERR_BAD_SSL_", "net::ERR_TLS_"},
+	failureTimeout:     {"net::ERR_TIMED_OUT", "net::ERR_CONNECTION_TIMED_OUT"},
+}
+
+// classifyFailure returns the category of the error that stopped a page
+// from being audited
+func classifyFailure(err error) failureCategory {
+	if err == nil {
+		return failureNone
+	}
+
+	var statusErr httpStatusError
+	if errors.As(err, &statusErr) {
+		return failureHTTPStatus
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return failureTimeout
+	}
+
+	for category, netErrors := range netErrorCategories {
+		for _, netErr := range netErrors {
+			if strings.Contains(err.Error(), netErr) {
+				return category
+			}
+		}
+	}
+
+	if strings.Contains(err.Error(), "net::ERR_") {
+		return failureNetwork
+	}
+
+	return failureBrowser
+}
@@ -0,0 +1,388 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+)
+
+// har is the root of a HAR 1.2 file (http://www.softwareishard.com/blog/har-12-spec/)
+type har struct {
+	Log harLog `json:"log"`
+}
+
+// harLog holds the pages and requests recorded for a single site
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Pages   []harPage  `json:"pages"`
+	Entries []harEntry `json:"entries"`
+}
+
+// harCreator identifies the application that created the HAR
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// harPage is a single page load
+type harPage struct {
+	StartedDateTime string         `json:"startedDateTime"`
+	ID              string         `json:"id"`
+	Title           string         `json:"title"`
+	PageTimings     harPageTimings `json:"pageTimings"`
+}
+
+// harPageTimings holds page load timings in milliseconds, -1 if unknown
+type harPageTimings struct {
+	OnContentLoad float64 `json:"onContentLoad"`
+	OnLoad        float64 `json:"onLoad"`
+}
+
+// harEntry is a single request made by a page - fields starting with an
+// underscore are custom ones, as exported by Chrome DevTools
+type harEntry struct {
+	Pageref         string      `json:"pageref"`
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	ServerIPAddress string      `json:"serverIPAddress,omitempty"`
+	Connection      string      `json:"connection,omitempty"`
+	ResourceType    string      `json:"_resourceType,omitempty"`
+	Priority        string      `json:"_priority,omitempty"`
+	Error           string      `json:"_error,omitempty"`
+}
+
+// harRequest holds the details of a request
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int64          `json:"headersSize"`
+	BodySize    int64          `json:"bodySize"`
+}
+
+// harResponse holds the details of a response
+type harResponse struct {
+	Status       int64          `json:"status"`
+	StatusText   string         `json:"statusText"`
+	HTTPVersion  string         `json:"httpVersion"`
+	Cookies      []harNameValue `json:"cookies"`
+	Headers      []harNameValue `json:"headers"`
+	Content      harContent     `json:"content"`
+	RedirectURL  string         `json:"redirectURL"`
+	HeadersSize  int64          `json:"headersSize"`
+	BodySize     int64          `json:"bodySize"`
+	TransferSize int64          `json:"_transferSize"`
+}
+
+// harNameValue is a header, cookie or query string parameter
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// harPostData holds a request's body
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+// harContent describes a response's body (bodies themselves aren't recorded)
+type harContent struct {
+	Size     int64  `json:"size"`
+	MimeType string `json:"mimeType"`
+}
+
+// harTimings holds the phases of a request in milliseconds, -1 if they
+// don't apply (connect includes ssl)
+type harTimings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+	SSL     float64 `json:"ssl"`
+}
+
+// writeHAR writes the requests recorded while loading each of the site's pages
+// to a HAR file in the given directory, returning the file path
+func writeHAR(dir, name string, result auditResult) (string, error) {
+	log := harLog{
+		Version: "1.2",
+		Creator: harCreator{Name: "site-auditor", Version: "1.0"},
+		Pages:   []harPage{},
+		Entries: []harEntry{},
+	}
+
+	for i, page := range result.pages {
+		if len(page.requests) == 0 {
+			continue // page didn't start loading
+		}
+
+		pageID := fmt.Sprintf("page_%d", i+1)
+		log.Pages = append(log.Pages, newHARPage(pageID, page))
+		for _, req := range page.requests {
+			log.Entries = append(log.Entries, newHAREntry(pageID, req))
+		}
+	}
+
+	data, err := json.MarshalIndent(har{Log: log}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode HAR: %w", err)
+	}
+
+	filename := filepath.Join(dir, fmt.Sprintf("har_%s.har", sanitiseFilename(name)))
+	err = os.WriteFile(filename, data, 0644)
+	if err != nil {
+		return "", fmt.Errorf("failed to write HAR: %w", err)
+	}
+
+	return filename, nil
+}
+
+// newHARPage converts an audited page into a HAR page, taking its load
+// timings from the vitals check (if it ran)
+func newHARPage(id string, page pageResult) harPage {
+	harPage := harPage{
+		StartedDateTime: formatHARTime(page.requests[0].wallTime),
+		ID:              id,
+		Title:           page.url,
+		PageTimings:     harPageTimings{OnContentLoad: -1, OnLoad: -1},
+	}
+
+	if vitals, ok := page.results[vitalsCheck.Name()].(webVitals); ok {
+		if vitals.DOMContentLoaded > 0 {
+			harPage.PageTimings.OnContentLoad = vitals.DOMContentLoaded
+		}
+		if vitals.Load > 0 {
+			harPage.PageTimings.OnLoad = vitals.Load
+		}
+	}
+
+	return harPage
+}
+
+// newHAREntry converts a recorded request into a HAR entry - failed requests
+// get an empty response, with the browser's error
+func newHAREntry(pageID string, req networkRequest) harEntry {
+	entry := harEntry{
+		Pageref:         pageID,
+		StartedDateTime: formatHARTime(req.wallTime),
+		Request: harRequest{
+			Method:      req.request.Method,
+			URL:         req.url,
+			Cookies:     []harNameValue{},
+			Headers:     harHeaders(req.request.Headers),
+			QueryString: harQueryString(req.url),
+			PostData:    harRequestBody(req.request),
+			HeadersSize: -1,
+		},
+		Response: harResponse{
+			Cookies:     []harNameValue{},
+			Headers:     []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    -1,
+		},
+		ResourceType: strings.ToLower(string(req.resourceType)),
+		Priority:     string(req.request.InitialPriority),
+		Error:        req.errorText,
+	}
+
+	if entry.Request.PostData != nil {
+		entry.Request.BodySize = int64(len(entry.Request.PostData.Text))
+	}
+
+	if req.response != nil {
+		resp := req.response
+		version := harHTTPVersion(resp.Protocol)
+		if len(resp.RequestHeaders) > 0 {
+			entry.Request.Headers = harHeaders(resp.RequestHeaders) // headers actually sent
+		}
+		entry.Request.HTTPVersion = version
+		entry.Response = harResponse{
+			Status:       resp.Status,
+			StatusText:   resp.StatusText,
+			HTTPVersion:  version,
+			Cookies:      []harNameValue{},
+			Headers:      harHeaders(resp.Headers),
+			Content:      harContent{Size: req.decoded, MimeType: resp.MimeType},
+			RedirectURL:  headerValue(resp.Headers, "Location"),
+			HeadersSize:  -1,
+			BodySize:     -1,
+			TransferSize: req.transferred,
+		}
+		entry.ServerIPAddress = resp.RemoteIPAddress
+		if resp.ConnectionID > 0 {
+			entry.Connection = fmt.Sprintf("%.0f", resp.ConnectionID)
+		}
+	}
+
+	entry.Timings = newHARTimings(req)
+	entry.Time = entry.Timings.total()
+
+	return entry
+}
+
+// newHARTimings splits a request's duration into its phases, using the
+// response's resource timing (missing e.g. for failed or cached requests)
+func newHARTimings(req networkRequest) harTimings {
+	timings := harTimings{Blocked: -1, DNS: -1, Connect: -1, SSL: -1}
+
+	var timing *network.ResourceTiming
+	if req.response != nil {
+		timing = req.response.Timing
+	}
+
+	if timing == nil {
+		// the whole duration is counted as waiting
+		if !req.sentAt.IsZero() && req.finishedAt.After(req.sentAt) {
+			timings.Wait = durationMs(req.finishedAt.Sub(req.sentAt))
+		}
+
+		return timings
+	}
+
+	// resource timing phases are offsets in ms from the request start
+	requestStart := cdp.MonotonicTimeEpoch.Add(time.Duration(timing.RequestTime * float64(time.Second)))
+	phase := func(start, end float64) float64 {
+		if start < 0 || end < start {
+			return -1
+		}
+
+		return end - start
+	}
+
+	// time queued before the request started, and stalled before connecting
+	firstPhase := timing.SendStart
+	for _, start := range []float64{timing.ConnectStart, timing.DNSStart} {
+		if start >= 0 {
+			firstPhase = start
+		}
+	}
+	timings.Blocked = max(0, firstPhase)
+	if !req.sentAt.IsZero() && requestStart.After(req.sentAt) {
+		timings.Blocked += durationMs(requestStart.Sub(req.sentAt))
+	}
+
+	timings.DNS = phase(timing.DNSStart, timing.DNSEnd)
+	timings.Connect = phase(timing.ConnectStart, timing.ConnectEnd)
+	timings.SSL = phase(timing.SslStart, timing.SslEnd)
+	timings.Send = max(0, phase(timing.SendStart, timing.SendEnd))
+	timings.Wait = max(0, phase(timing.SendEnd, timing.ReceiveHeadersEnd))
+
+	headersEnd := requestStart.Add(time.Duration(timing.ReceiveHeadersEnd * float64(time.Millisecond)))
+	if req.finishedAt.After(headersEnd) {
+		timings.Receive = durationMs(req.finishedAt.Sub(headersEnd))
+	}
+
+	return timings
+}
+
+// total returns the request's total duration, summed from its phases
+// (ssl is already part of connect)
+func (t harTimings) total() float64 {
+	total := 0.0
+	for _, phase := range []float64{t.Blocked, t.DNS, t.Connect, t.Send, t.Wait, t.Receive} {
+		total += max(0, phase)
+	}
+
+	return total
+}
+
+// harHeaders converts headers into HAR name/value pairs, sorted by name -
+// headers with multiple values (joined by newlines) get a pair per value
+func harHeaders(headers network.Headers) []harNameValue {
+	pairs := []harNameValue{}
+	for name, value := range headers {
+		for line := range strings.SplitSeq(fmt.Sprint(value), "\n") {
+			pairs = append(pairs, harNameValue{Name: name, Value: line})
+		}
+	}
+
+	slices.SortStableFunc(pairs, func(a, b harNameValue) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+
+	return pairs
+}
+
+// harQueryString returns the query parameters of a URL, in order
+func harQueryString(requestURL string) []harNameValue {
+	pairs := []harNameValue{}
+
+	parsed, err := url.Parse(requestURL)
+	if err != nil || parsed.RawQuery == "" {
+		return pairs
+	}
+
+	for param := range strings.SplitSeq(parsed.RawQuery, "&") {
+		name, value, _ := strings.Cut(param, "=")
+		name, _ = url.QueryUnescape(name)
+		value, _ = url.QueryUnescape(value)
+		pairs = append(pairs, harNameValue{Name: name, Value: value})
+	}
+
+	return pairs
+}
+
+// harRequestBody returns a request's body, if it has one the browser reported
+func harRequestBody(req *network.Request) *harPostData {
+	if !req.HasPostData || len(req.PostDataEntries) == 0 {
+		return nil
+	}
+
+	var body strings.Builder
+	for _, entry := range req.PostDataEntries {
+		data, err := base64.StdEncoding.DecodeString(entry.Bytes)
+		if err != nil {
+			continue
+		}
+
+		body.Write(data)
+	}
+
+	return &harPostData{MimeType: headerValue(req.Headers, "Content-Type"), Text: body.String()}
+}
+
+// harHTTPVersion converts a response protocol (as reported by Chrome) into
+// an HTTP version
+func harHTTPVersion(protocol string) string {
+	switch strings.ToLower(protocol) {
+	case "h2":
+		return "HTTP/2.0"
+	case "h3":
+		return "HTTP/3.0"
+	case "":
+		return ""
+	default:
+		return strings.ToUpper(protocol)
+	}
+}
+
+// formatHARTime formats a time as ISO 8601, with milliseconds
+func formatHARTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000Z07:00")
+}
+
+// durationMs converts a duration to milliseconds
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
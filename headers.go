@@ -0,0 +1,373 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/chromedp/cdproto/network"
+)
+
+const (
+	minHSTSMaxAge  = 180 * 24 * 60 * 60 // 6 months, in seconds
+	hstsPreloadAge = 365 * 24 * 60 * 60 // min max-age accepted by the HSTS preload list
+)
+
+// security header grades, by minimum score (out of 100)
+var headerGrades = []struct {
+	grade    string
+	minScore int
+}{
+	{"A", 90}, {"B", 75}, {"C", 60}, {"D", 45}, {"E", 30}, {"F", 0},
+}
+
+// referrer policies by how much of the URL they leak to other sites
+var (
+	strongReferrerPolicies = []string{"no-referrer", "same-origin", "strict-origin", "strict-origin-when-cross-origin"}
+	weakReferrerPolicies   = []string{"origin", "origin-when-cross-origin", "no-referrer-when-downgrade"}
+)
+
+// headerFinding holds the grading of a single security header
+type headerFinding struct {
+	Header   string   `json:"header"`
+	Value    string   `json:"value,omitempty"`
+	Score    int      `json:"score"`
+	MaxScore int      `json:"maxScore"`
+	Grade    string   `json:"grade"`
+	Notes    []string `json:"notes,omitempty"` // why points were taken off
+}
+
+// String formats the finding for output
+func (f headerFinding) String() string {
+	finding := fmt.Sprintf("%s (%s, %d/%d)", f.Header, f.Grade, f.Score, f.MaxScore)
+	if len(f.Notes) > 0 {
+		finding += ": " + strings.Join(f.Notes, "; ")
+	}
+
+	return finding
+}
+
+// securityHeaders holds the grading of the page's security headers
+type securityHeaders struct {
+	Grade    string          `json:"grade"`
+	Score    int             `json:"score"` // out of 100
+	Missing  []string        `json:"missing"`
+	Findings []headerFinding `json:"findings"`
+}
+
+// issues counts headers that are missing or not configured as well as they could be
+func (h securityHeaders) issues() int {
+	issues := 0
+	for _, finding := range h.Findings {
+		if finding.Score < finding.MaxScore {
+			issues++
+		}
+	}
+
+	return issues
+}
+
+// gradeSecurityHeaders parses and grades the security headers of the page's
+// main document response - each header is scored, and the total gives the grade
+func gradeSecurityHeaders(headers network.Headers) securityHeaders {
+	csp := parseCSP(headerValue(headers, "Content-Security-Policy"))
+
+	findings := []headerFinding{
+		gradeCSP(headerValue(headers, "Content-Security-Policy"), csp, headerValue(headers, "Content-Security-Policy-Report-Only")),
+		gradeHSTS(headerValue(headers, "Strict-Transport-Security")),
+		gradeFrameOptions(headerValue(headers, "X-Frame-Options"), csp),
+		gradeContentTypeOptions(headerValue(headers, "X-Content-Type-Options")),
+		gradeReferrerPolicy(headerValue(headers, "Referrer-Policy")),
+		gradePermissionsPolicy(headerValue(headers, "Permissions-Policy")),
+	}
+
+	result := securityHeaders{Missing: []string{}, Findings: findings}
+	for i, finding := range findings {
+		findings[i].Grade = gradeScore(finding.Score * 100 / finding.MaxScore)
+		result.Score += finding.Score
+
+		// frame-ancestors stands in for X-Frame-Options when grading, but the header is still missing
+		if headerValue(headers, finding.Header) == "" {
+			result.Missing = append(result.Missing, finding.Header)
+		}
+	}
+	result.Grade = gradeScore(result.Score)
+
+	return result
+}
+
+// gradeScore converts a score out of 100 into a grade
+func gradeScore(score int) string {
+	for _, grade := range headerGrades {
+		if score >= grade.minScore {
+			return grade.grade
+		}
+	}
+
+	return "F"
+}
+
+// contentSecurityPolicy holds the directives of a CSP, by name - with
+// multiple policies, the one restricting scripts the most is kept
+type contentSecurityPolicy map[string][]string
+
+// parseCSP parses a Content-Security-Policy header value - multiple policies
+// (from repeated headers) are comma or newline separated
+func parseCSP(value string) contentSecurityPolicy {
+	var best contentSecurityPolicy
+	bestScore := -1
+
+	for policyValue := range strings.FieldsFuncSeq(value, func(r rune) bool { return r == ',' || r == '\n' }) {
+		policy := contentSecurityPolicy{}
+		for directive := range strings.SplitSeq(policyValue, ";") {
+			fields := strings.Fields(strings.ToLower(directive))
+			if len(fields) == 0 {
+				continue
+			}
+
+			// only the first occurrence of a directive counts
+			if _, ok := policy[fields[0]]; !ok {
+				policy[fields[0]] = fields[1:]
+			}
+		}
+
+		if score, _ := policy.scriptScore(); score > bestScore {
+			best, bestScore = policy, score
+		}
+	}
+
+	return best
+}
+
+// scriptSources returns the sources scripts are allowed from, falling back
+// to default-src
+func (p contentSecurityPolicy) scriptSources() ([]string, bool) {
+	if sources, ok := p["script-src"]; ok {
+		return sources, true
+	}
+
+	sources, ok := p["default-src"]
+	return sources, ok
+}
+
+// scriptScore scores how well the policy restricts scripts (out of 25),
+// with notes on what it allows - a policy leaving scripts unrestricted scores
+// nothing, as it gives no more protection against XSS than having no CSP
+func (p contentSecurityPolicy) scriptScore() (int, []string) {
+	sources, ok := p.scriptSources()
+	if !ok {
+		return 0, []string{"no script-src or default-src, so scripts aren't restricted"}
+	}
+
+	score, notes := 25, []string{}
+
+	// nonces and hashes make browsers ignore 'unsafe-inline', and 'strict-dynamic'
+	// makes them ignore host and scheme sources
+	hasNonce := slices.ContainsFunc(sources, func(source string) bool {
+		return strings.HasPrefix(source, "'nonce-") || strings.HasPrefix(source, "'sha")
+	})
+	strictDynamic := slices.Contains(sources, "'strict-dynamic'")
+
+	if slices.Contains(sources, "'unsafe-inline'") && !hasNonce {
+		score -= 10
+		notes = append(notes, "allows 'unsafe-inline' scripts")
+	}
+
+	if slices.Contains(sources, "'unsafe-eval'") {
+		score -= 5
+		notes = append(notes, "allows 'unsafe-eval'")
+	}
+
+	if !strictDynamic {
+		wildcards := []string{}
+		for _, source := range sources {
+			if source == "*" || source == "http:" || source == "https:" || source == "data:" {
+				wildcards = append(wildcards, source)
+			}
+		}
+
+		if len(wildcards) > 0 {
+			score = 0
+			notes = append(notes, fmt.Sprintf("allows scripts from any source (%s), so scripts aren't restricted", strings.Join(wildcards, " ")))
+		}
+	}
+
+	return max(score, 0), notes
+}
+
+// gradeCSP grades the Content-Security-Policy header on how well it restricts
+// scripts - report only policies aren't enforced, so don't count
+func gradeCSP(value string, policy contentSecurityPolicy, reportOnly string) headerFinding {
+	finding := headerFinding{Header: "Content-Security-Policy", Value: value, MaxScore: 25}
+	if value == "" {
+		finding.Notes = append(finding.Notes, "missing")
+		if reportOnly != "" {
+			finding.Notes = append(finding.Notes, "only set as report only, so isn't enforced")
+		}
+
+		return finding
+	}
+
+	finding.Score, finding.Notes = policy.scriptScore()
+	return finding
+}
+
+// gradeHSTS grades the Strict-Transport-Security header's max-age, and
+// whether it covers subdomains
+func gradeHSTS(value string) headerFinding {
+	finding := headerFinding{Header: "Strict-Transport-Security", Value: value, MaxScore: 25}
+	if value == "" {
+		finding.Notes = append(finding.Notes, "missing")
+		return finding
+	}
+
+	maxAge := -1
+	directives := []string{}
+	for directive := range strings.SplitSeq(strings.ToLower(value), ";") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		directives = append(directives, name)
+
+		if name == "max-age" {
+			age, err := strconv.Atoi(strings.Trim(arg, `" `))
+			if err == nil {
+				maxAge = age
+			}
+		}
+	}
+
+	switch {
+	case maxAge < 0:
+		finding.Notes = append(finding.Notes, "missing or invalid max-age, so it's ignored")
+		return finding
+	case maxAge == 0:
+		finding.Notes = append(finding.Notes, "max-age=0 turns HSTS off")
+		return finding
+	case maxAge < minHSTSMaxAge:
+		finding.Score = 15
+		age := fmt.Sprintf("%d seconds", maxAge)
+		if maxAge >= 24*60*60 {
+			age = fmt.Sprintf("%d days", maxAge/(24*60*60))
+		}
+		finding.Notes = append(finding.Notes, fmt.Sprintf("max-age of %s is under 6 months", age))
+	default:
+		finding.Score = 25
+	}
+
+	if !slices.Contains(directives, "includesubdomains") {
+		finding.Score -= 5
+		finding.Notes = append(finding.Notes, "no includeSubDomains")
+	}
+
+	// preloading isn't scored, since not every site can commit to it
+	if slices.Contains(directives, "preload") && (maxAge < hstsPreloadAge || !slices.Contains(directives, "includesubdomains")) {
+		finding.Notes = append(finding.Notes, "preload needs includeSubDomains and a max-age of at least a year")
+	}
+
+	return finding
+}
+
+// gradeFrameOptions grades clickjacking protection - CSP frame-ancestors
+// supersedes X-Frame-Options in browsers supporting it
+func gradeFrameOptions(value string, policy contentSecurityPolicy) headerFinding {
+	finding := headerFinding{Header: "X-Frame-Options", Value: value, MaxScore: 15}
+
+	if ancestors, ok := policy["frame-ancestors"]; ok {
+		if slices.Contains(ancestors, "*") || slices.Contains(ancestors, "https:") || slices.Contains(ancestors, "http:") {
+			finding.Notes = append(finding.Notes, "CSP frame-ancestors allows framing by any site")
+			return finding
+		}
+
+		finding.Score = 15
+		if finding.Value == "" {
+			finding.Value = "frame-ancestors " + strings.Join(ancestors, " ")
+		}
+
+		return finding
+	}
+
+	switch strings.ToUpper(strings.TrimSpace(value)) {
+	case "DENY", "SAMEORIGIN":
+		finding.Score = 15
+	case "":
+		finding.Notes = append(finding.Notes, "missing, and no CSP frame-ancestors")
+	default:
+		if strings.HasPrefix(strings.ToUpper(value), "ALLOW-FROM") {
+			finding.Score = 5
+			finding.Notes = append(finding.Notes, "ALLOW-FROM is ignored by modern browsers - use CSP frame-ancestors")
+		} else {
+			finding.Notes = append(finding.Notes, "invalid value, should be DENY or SAMEORIGIN")
+		}
+	}
+
+	return finding
+}
+
+// gradeContentTypeOptions checks X-Content-Type-Options is set to nosniff,
+// its only valid value
+func gradeContentTypeOptions(value string) headerFinding {
+	finding := headerFinding{Header: "X-Content-Type-Options", Value: value, MaxScore: 15}
+
+	switch {
+	case value == "":
+		finding.Notes = append(finding.Notes, "missing")
+	case strings.EqualFold(strings.TrimSpace(value), "nosniff"):
+		finding.Score = 15
+	default:
+		finding.Notes = append(finding.Notes, "invalid value, should be nosniff")
+	}
+
+	return finding
+}
+
+// gradeReferrerPolicy grades the Referrer-Policy header on how much of page
+// URLs it leaks to other sites - with a list of policies, the last one the
+// browser supports applies
+func gradeReferrerPolicy(value string) headerFinding {
+	finding := headerFinding{Header: "Referrer-Policy", Value: value, MaxScore: 10}
+	if value == "" {
+		// browsers default to strict-origin-when-cross-origin
+		finding.Score = 5
+		finding.Notes = append(finding.Notes, "missing, so left to the browser's default")
+		return finding
+	}
+
+	policy := ""
+	for token := range strings.SplitSeq(strings.ToLower(value), ",") {
+		token = strings.TrimSpace(token)
+		if slices.Contains(strongReferrerPolicies, token) || slices.Contains(weakReferrerPolicies, token) || token == "unsafe-url" {
+			policy = token
+		}
+	}
+
+	switch {
+	case slices.Contains(strongReferrerPolicies, policy):
+		finding.Score = 10
+	case slices.Contains(weakReferrerPolicies, policy):
+		finding.Score = 5
+		finding.Notes = append(finding.Notes, fmt.Sprintf("%s sends more of the URL than needed to other sites", policy))
+	case policy == "unsafe-url":
+		finding.Notes = append(finding.Notes, "unsafe-url sends full URLs to any site, even over HTTP")
+	default:
+		finding.Notes = append(finding.Notes, "no valid policy")
+	}
+
+	return finding
+}
+
+// gradePermissionsPolicy checks the Permissions-Policy header restricts
+// at least one browser feature
+func gradePermissionsPolicy(value string) headerFinding {
+	finding := headerFinding{Header: "Permissions-Policy", Value: value, MaxScore: 10}
+
+	switch {
+	case value == "":
+		finding.Notes = append(finding.Notes, "missing")
+	case !strings.Contains(value, "="):
+		finding.Notes = append(finding.Notes, "no valid feature directives")
+	default:
+		finding.Score = 10
+	}
+
+	return finding
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// HTMLSink handles writing audit results to a self-contained HTML report,
+// with a summary table and a card per audited site
+// - it satisfies the sink interface
+type HTMLSink struct {
+	name       string
+	outputFile string
+}
+
+// NewHTMLSink creates a new HTMLSink instance
+func NewHTMLSink(outputFile string) (*HTMLSink, error) {
+	newSink := HTMLSink{name: "html sink", outputFile: outputFile}
+	err := createOutputFile(newSink.outputFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed html output file validation/creation: %w", err)
+	}
+
+	return &newSink, nil
+}
+
+// Name returns the sink name
+func (s *HTMLSink) Name() string {
+	return s.name
+}
+
+// htmlReport holds the data rendered by the report template
+type htmlReport struct {
+	Generated  string
+	Throttle   string // throttling profile, if pages were throttled
+	ShowDevice bool
+	ShowLCP    bool
+	ShowScore  bool
+	ShowSecure bool
+	ShowBudget bool
+	Sites      []htmlSite
+}
+
+// htmlSite holds the data rendered for a single audited site
+type htmlSite struct {
+	ID         string
+	Website    string
+	Device     string
+	Issues     int
+	LCP        string
+	Score      string
+	Secure     string
+	Failure    failureCategory
+	Verdict    string       // budgets verdict, if budgets are set
+	Budgets    []string     // budget results, formatted for output
+	Screenshot template.URL // embedded image data URI
+	HAR        string       // path of the exported HAR file, relative to the report
+	Sections   []htmlSection
+	Errors     []string
+	Pages      []htmlPage // all audited pages, if multiple were
+}
+
+// htmlSection holds a single check's output values
+type htmlSection struct {
+	Title  string
+	Values []string
+}
+
+// htmlPage holds an audited page's summary
+type htmlPage struct {
+	URL    string
+	Issues int
+}
+
+// WriteResults writes the results to the output HTML report
+func (s *HTMLSink) WriteResults(results []auditResult) error {
+	if s == nil || s.outputFile == "" {
+		return fmt.Errorf("nil html sink")
+	}
+
+	tmpl, err := template.New("report").Parse(reportTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse report template: %w", err)
+	}
+
+	report := htmlReport{
+		Generated:  time.Now().Format("2 Jan 2006 15:04"),
+		ShowDevice: multipleDevices(results),
+		ShowBudget: len(results[0].budgets) > 0,
+	}
+	if results[0].throttle != "none" {
+		report.Throttle = results[0].throttle
+	}
+	for _, check := range results[0].checks {
+		switch check.Name() {
+		case lcpCheck.Name():
+			report.ShowLCP = true
+		case mobileCheck.Name():
+			report.ShowScore = true
+		case securityCheck.Name():
+			report.ShowSecure = true
+		}
+	}
+
+	for i, res := range results {
+		report.Sites = append(report.Sites, s.newSite(i, res))
+	}
+
+	outFile, err := os.Create(s.outputFile)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	defer outFile.Close()
+
+	err = tmpl.Execute(outFile, report)
+	if err != nil {
+		return fmt.Errorf("failed to write to file: %w", err)
+	}
+
+	return nil
+}
+
+// newSite prepares a single result for rendering, using its entry page
+func (s *HTMLSink) newSite(index int, res auditResult) htmlSite {
+	entry := res.pages[0]
+
+	site := htmlSite{
+		ID:      fmt.Sprintf("site-%d", index),
+		Website: res.website,
+		Device:  res.device,
+		Issues:  res.totalIssues(),
+		Errors:  entry.errors(res.checks),
+		Failure: entry.failure,
+		Verdict: res.budgetVerdict(),
+	}
+
+	if len(entry.timings) > 0 {
+		section := htmlSection{Title: fmt.Sprintf("Timing Samples (%d runs)", res.runs)}
+		for _, metric := range timingMetrics {
+			if stats, ok := entry.timings[metric]; ok {
+				section.Values = append(section.Values, timingLabels[metric]+": "+stats.String())
+			}
+		}
+
+		site.Sections = append(site.Sections, section)
+	}
+
+	for _, budget := range res.budgets {
+		site.Budgets = append(site.Budgets, fmt.Sprintf("%s: %s (%s)", budget.Budget, budget.formatValue(), budget.Page))
+	}
+
+	if lcp, ok := entry.results[lcpCheck.Name()].(float64); ok {
+		site.LCP = fmt.Sprintf("%.0f", lcp)
+	}
+
+	if responsiveIssues, ok := entry.results[mobileCheck.Name()].([]string); ok {
+		if score, ok := responsiveScore(responsiveIssues); ok {
+			site.Score = fmt.Sprint(score)
+		}
+	}
+
+	if secure, ok := entry.results[securityCheck.Name()].(bool); ok {
+		site.Secure = boolToEmoji(secure)
+	}
+
+	if path, ok := entry.results[screenshotCheck.Name()].(string); ok && path != "" {
+		site.Screenshot = s.embedImage(path)
+	}
+
+	if res.harFile != "" {
+		site.HAR = s.relativePath(res.harFile)
+	}
+
+	for _, check := range res.checks {
+		// screenshot is shown as an image
+		if check.Name() == screenshotCheck.Name() {
+			continue
+		}
+
+		values := check.Values(entry.results[check.Name()])
+		for i, column := range check.Columns() {
+			section := htmlSection{Title: column}
+			for value := range strings.SplitSeq(values[i], ";\n") {
+				if value != "" {
+					section.Values = append(section.Values, value)
+				}
+			}
+
+			site.Sections = append(site.Sections, section)
+		}
+	}
+
+	if res.multiPage {
+		for _, page := range res.pages {
+			site.Pages = append(site.Pages, htmlPage{URL: page.url, Issues: res.pageIssues(page)})
+		}
+	}
+
+	return site
+}
+
+// relativePath returns the path relative to the report's folder, so links
+// keep working when both are moved together
+func (s *HTMLSink) relativePath(path string) string {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+
+	absDir, err := filepath.Abs(filepath.Dir(s.outputFile))
+	if err != nil {
+		return path
+	}
+
+	relPath, err := filepath.Rel(absDir, absPath)
+	if err != nil {
+		return path
+	}
+
+	return filepath.ToSlash(relPath)
+}
+
+// embedImage reads an image from disk and returns it as a data URI, so the
+// report works offline as a single file
+func (s *HTMLSink) embedImage(path string) template.URL {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Printf("⚠️ failed to embed screenshot %s: %v\n", path, err)
+		return ""
+	}
+
+	return template.URL("data:image/jpeg;base64," + base64.StdEncoding.EncodeToString(data))
+}
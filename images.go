@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// image findings below this estimated saving aren't worth reporting
+// (same cut-off as Lighthouse)
+const minImageSavings = 4 * 1024
+
+// estimates used for potential savings - rough, since images aren't re-encoded
+const (
+	oversizedRatio      = 1.5  // natural pixels over rendered ones before an image is oversized
+	modernFormatSavings = 0.3  // WebP/AVIF are typically 25-35% smaller than JPEG/PNG
+	maxBytesPerPixel    = 0.5  // lossy images above are poorly compressed
+	targetBytesPerPixel = 0.25 // roughly JPEG at quality 85
+)
+
+// image formats WebP/AVIF would usually be smaller than
+var legacyImageTypes = []string{"image/jpeg", "image/jpg", "image/png", "image/gif"}
+
+// image formats stored without (or with barely any) compression
+var uncompressedImageTypes = []string{"image/bmp", "image/x-ms-bmp", "image/tiff"}
+
+// pageImage is an image found on the page
+type pageImage struct {
+	URL            string `json:"url"`
+	NaturalWidth   int64  `json:"naturalWidth"`
+	NaturalHeight  int64  `json:"naturalHeight"`
+	RenderedWidth  int64  `json:"renderedWidth"`
+	RenderedHeight int64  `json:"renderedHeight"`
+	Visible        bool   `json:"visible"`
+	BelowFold      bool   `json:"belowFold"`
+	Lazy           bool   `json:"lazy"`
+	HasDimensions  bool   `json:"hasDimensions"`
+}
+
+// imageIssue is a single optimisation opportunity found for an image
+type imageIssue struct {
+	URL          string `json:"url"`
+	Issue        string `json:"issue"` // oversized, legacy-format, not-lazy, missing-dimensions or uncompressed
+	Detail       string `json:"detail"`
+	Bytes        int64  `json:"bytes"`        // transferred size of the image
+	SavingsBytes int64  `json:"savingsBytes"` // estimated, or bytes deferred for lazy loading
+}
+
+// String formats the issue for output
+func (i imageIssue) String() string {
+	if i.SavingsBytes == 0 {
+		return fmt.Sprintf("[%s] %s - %s", i.Issue, i.URL, i.Detail)
+	}
+
+	// lazy loading defers bytes from the initial load, rather than saving them
+	verb := "save"
+	if i.Issue == "not-lazy" {
+		verb = "defer"
+	}
+
+	return fmt.Sprintf("[%s] %s - %s (%s ~%s)", i.Issue, i.URL, i.Detail, verb, formatBytes(i.SavingsBytes))
+}
+
+// imageAudit holds the image optimisation issues found on a page
+type imageAudit struct {
+	Issues       []imageIssue `json:"issues"`
+	SavingsBytes int64        `json:"savingsBytes"` // estimated total, counting each image's largest saving
+}
+
+// auditImages checks the page's images for optimisation issues, using their
+// requests for sizes and formats
+func auditImages(images []pageImage, requests *networkRecorder) imageAudit {
+	audit := imageAudit{Issues: []imageIssue{}}
+	seen := map[string]bool{}
+
+	for _, image := range images {
+		// the same image can be shown more than once
+		if seen[image.URL] {
+			continue
+		}
+		seen[image.URL] = true
+
+		bytes, mimeType := int64(0), ""
+		if req, ok := requests.find(image.URL); ok {
+			bytes = req.transferred
+			if req.response != nil {
+				mimeType = strings.ToLower(req.response.MimeType)
+			}
+		}
+
+		issues := checkImage(image, bytes, mimeType)
+
+		largest := int64(0)
+		for _, issue := range issues {
+			largest = max(largest, issue.SavingsBytes)
+		}
+
+		audit.Issues = append(audit.Issues, issues...)
+		audit.SavingsBytes += largest
+	}
+
+	return audit
+}
+
+// checkImage returns the optimisation issues of a single image
+func checkImage(image pageImage, bytes int64, mimeType string) []imageIssue {
+	issues := []imageIssue{}
+	add := func(issue, detail string, savings int64) {
+		issues = append(issues, imageIssue{
+			URL:          image.URL,
+			Issue:        issue,
+			Detail:       detail,
+			Bytes:        bytes,
+			SavingsBytes: savings,
+		})
+	}
+
+	naturalPixels := float64(image.NaturalWidth * image.NaturalHeight)
+	renderedPixels := float64(image.RenderedWidth * image.RenderedHeight)
+
+	if image.Visible && renderedPixels > 0 && naturalPixels > renderedPixels*oversizedRatio {
+		savings := int64(float64(bytes) * (1 - renderedPixels/naturalPixels))
+		if savings >= minImageSavings {
+			detail := fmt.Sprintf("%dx%d served, %dx%d rendered",
+				image.NaturalWidth, image.NaturalHeight, image.RenderedWidth, image.RenderedHeight)
+			add("oversized", detail, savings)
+		}
+	}
+
+	if slices.Contains(legacyImageTypes, mimeType) {
+		savings := int64(float64(bytes) * modernFormatSavings)
+		if savings >= minImageSavings {
+			add("legacy-format", mimeType+" could be WebP/AVIF", savings)
+		}
+	}
+
+	// savings of compressing to a typical lossy size, from the image's pixel count
+	if naturalPixels > 0 {
+		bytesPerPixel := float64(bytes) / naturalPixels
+		uncompressed := slices.Contains(uncompressedImageTypes, mimeType) ||
+			(mimeType == "image/jpeg" && bytesPerPixel > maxBytesPerPixel)
+
+		savings := bytes - int64(naturalPixels*targetBytesPerPixel)
+		if uncompressed && savings >= minImageSavings {
+			add("uncompressed", fmt.Sprintf("%s at %.2f bytes per pixel", mimeType, bytesPerPixel), savings)
+		}
+	}
+
+	if image.Visible && image.BelowFold && !image.Lazy && bytes >= minImageSavings {
+		add("not-lazy", "below the fold without loading=\"lazy\"", bytes)
+	}
+
+	if image.Visible && !image.HasDimensions {
+		add("missing-dimensions", "no width/height, so it shifts layout when loaded", 0)
+	}
+
+	return issues
+}
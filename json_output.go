@@ -0,0 +1,201 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// jsonResult is the JSON representation of an audit result, keeping
+// check results typed
+type jsonResult struct {
+	Website     string         `json:"website"`
+	Device      string         `json:"device"`
+	Throttle    string         `json:"throttle"`
+	Runs        int            `json:"runs"`
+	MultiPage   bool           `json:"multiPage"`
+	Pages       []jsonPage     `json:"pages"`
+	TotalIssues int            `json:"totalIssues"`
+	WorstPage   string         `json:"worstPage,omitempty"`
+	Budgets     []budgetResult `json:"budgets,omitempty"`
+	Verdict     string         `json:"budgetVerdict,omitempty"` // "pass" or "fail", if budgets are set
+	HAR         string         `json:"har,omitempty"`           // path of the exported HAR file
+}
+
+// jsonPage is the JSON representation of a single page's results
+type jsonPage struct {
+	URL         string                 `json:"url"`
+	Attempts    int                    `json:"attempts"`
+	Failure     failureCategory        `json:"failure,omitempty"`
+	HTTPStatus  int64                  `json:"httpStatus,omitempty"`
+	Checks      map[string]any         `json:"checks"`
+	CheckErrors map[string]string      `json:"checkErrors,omitempty"`
+	AuditErrors []string               `json:"auditErrors,omitempty"`
+	Timings     map[string]timingStats `json:"timings,omitempty"`
+	Issues      int                    `json:"issues"`
+}
+
+// newJSONResult converts an audit result into its JSON representation
+func newJSONResult(res auditResult) jsonResult {
+	jsonRes := jsonResult{
+		Website:     res.website,
+		Device:      res.device,
+		Throttle:    res.throttle,
+		Runs:        res.runs,
+		MultiPage:   res.multiPage,
+		Pages:       []jsonPage{},
+		TotalIssues: res.totalIssues(),
+		Budgets:     res.budgets,
+		Verdict:     res.budgetVerdict(),
+		HAR:         res.harFile,
+	}
+
+	if res.multiPage {
+		worst, _ := res.worstPage()
+		jsonRes.WorstPage = worst.url
+	}
+
+	for _, page := range res.pages {
+		checks := map[string]any{}
+		for _, check := range res.checks {
+			checks[check.Name()] = page.results[check.Name()]
+		}
+
+		jsonRes.Pages = append(jsonRes.Pages, jsonPage{
+			URL:         page.url,
+			Attempts:    page.attempts,
+			Failure:     page.failure,
+			HTTPStatus:  page.httpStatus,
+			Checks:      checks,
+			CheckErrors: page.checkErrs,
+			AuditErrors: page.auditErrs,
+			Timings:     page.timings,
+			Issues:      res.pageIssues(page),
+		})
+	}
+
+	return jsonRes
+}
+
+// JSONSink handles writing audit results to a JSON file, as an array of results
+// - it satisfies the sink interface
+type JSONSink struct {
+	name       string
+	outputFile string
+}
+
+// NewJSONSink creates a new JSONSink instance
+func NewJSONSink(outputFile string) (*JSONSink, error) {
+	newSink := JSONSink{name: "json sink", outputFile: outputFile}
+	err := createOutputFile(newSink.outputFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed json output file validation/creation: %w", err)
+	}
+
+	return &newSink, nil
+}
+
+// Name returns the sink name
+func (s *JSONSink) Name() string {
+	return s.name
+}
+
+// WriteResults writes the results to the output JSON
+func (s *JSONSink) WriteResults(results []auditResult) error {
+	if s == nil || s.outputFile == "" {
+		return fmt.Errorf("nil json sink")
+	}
+
+	jsonResults := make([]jsonResult, 0, len(results))
+	for _, res := range results {
+		jsonResults = append(jsonResults, newJSONResult(res))
+	}
+
+	data, err := json.MarshalIndent(jsonResults, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode results: %w", err)
+	}
+
+	err = os.WriteFile(s.outputFile, data, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to write to file: %w", err)
+	}
+
+	return nil
+}
+
+// JSONLSink handles writing audit results to a JSON Lines file, one result per
+// line, streaming them as they're audited
+// - it satisfies the stream sink interface
+type JSONLSink struct {
+	name       string
+	outputFile string
+	mu         sync.Mutex
+}
+
+// NewJSONLSink creates a new JSONLSink instance
+func NewJSONLSink(outputFile string) (*JSONLSink, error) {
+	newSink := JSONLSink{name: "jsonl sink", outputFile: outputFile}
+	err := createOutputFile(newSink.outputFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed jsonl output file validation/creation: %w", err)
+	}
+
+	return &newSink, nil
+}
+
+// Name returns the sink name
+func (s *JSONLSink) Name() string {
+	return s.name
+}
+
+// WriteResult appends a single result to the output JSONL, as soon as it's audited
+func (s *JSONLSink) WriteResult(result auditResult) error {
+	if s == nil || s.outputFile == "" {
+		return fmt.Errorf("nil jsonl sink")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	outFile, err := os.OpenFile(s.outputFile, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	defer outFile.Close()
+
+	err = json.NewEncoder(outFile).Encode(newJSONResult(result))
+	if err != nil {
+		return fmt.Errorf("failed to write to file: %w", err)
+	}
+
+	return nil
+}
+
+// WriteResults rewrites the output JSONL with all results, in input order
+// (streamed results are written in the order they finished)
+func (s *JSONLSink) WriteResults(results []auditResult) error {
+	if s == nil || s.outputFile == "" {
+		return fmt.Errorf("nil jsonl sink")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	outFile, err := os.Create(s.outputFile)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	defer outFile.Close()
+
+	encoder := json.NewEncoder(outFile)
+	for _, res := range results {
+		err = encoder.Encode(newJSONResult(res))
+		if err != nil {
+			return fmt.Errorf("failed to write to file: %w", err)
+		}
+	}
+
+	return nil
+}
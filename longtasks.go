@@ -0,0 +1,71 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+const (
+	heavyScriptMs    = 250 // scripts running longer than this in long frames are reported as issues
+	maxReportScripts = 5   // number of scripts responsible listed in output
+)
+
+// longTask is a main thread task over 50ms, timed from navigation start
+type longTask struct {
+	Start    float64 `json:"start"`
+	Duration float64 `json:"duration"`
+}
+
+// taskScript is a script responsible for keeping the main thread busy
+type taskScript struct {
+	URL        string  `json:"url"` // "inline" for scripts without a source URL
+	DurationMs float64 `json:"durationMs"`
+}
+
+// String formats the script for output
+func (s taskScript) String() string {
+	return fmt.Sprintf("%s (%.0f ms)", s.URL, s.DurationMs)
+}
+
+// mainThreadActivity summarises long tasks seen while loading the page
+type mainThreadActivity struct {
+	LongTasks     int          `json:"longTasks"`
+	LongestTaskMs float64      `json:"longestTaskMs"`
+	ScriptMs      float64      `json:"scriptMs"` // script execution within long animation frames
+	Scripts       []taskScript `json:"scripts"`  // longest running first
+}
+
+// newMainThreadActivity summarises long tasks, along with the time each script
+// spent running in long animation frames - total blocking time is left to the
+// vitals check
+func newMainThreadActivity(tasks []longTask, scriptTime map[string]float64) mainThreadActivity {
+	activity := mainThreadActivity{LongTasks: len(tasks), Scripts: []taskScript{}}
+
+	for _, task := range tasks {
+		activity.LongestTaskMs = max(activity.LongestTaskMs, task.Duration)
+	}
+
+	for scriptURL, ms := range scriptTime {
+		activity.ScriptMs += ms
+		activity.Scripts = append(activity.Scripts, taskScript{URL: scriptURL, DurationMs: ms})
+	}
+
+	slices.SortFunc(activity.Scripts, func(a, b taskScript) int {
+		return cmp.Or(cmp.Compare(b.DurationMs, a.DurationMs), cmp.Compare(a.URL, b.URL))
+	})
+
+	return activity
+}
+
+// heavyScripts counts scripts running for too long in long animation frames
+func (m mainThreadActivity) heavyScripts() int {
+	heavy := 0
+	for _, script := range m.Scripts {
+		if script.DurationMs > heavyScriptMs {
+			heavy++
+		}
+	}
+
+	return heavy
+}
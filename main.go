@@ -5,6 +5,7 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"time"
 )
 
 type config struct {
@@ -12,9 +13,25 @@ type config struct {
 	scrape        string
 	input         string
 	output        string
+	format        string
 	checks        string
 	important     bool
 	screenshotDir string
+	concurrency   int
+	serialLoad    bool
+	crawlDepth    int
+	crawlPages    int
+	exactURLs     bool
+	throttle      string
+	budgets       string
+	runs          int
+	har           bool
+	certExpiry    int
+	devices       string
+	checkpoint    string
+	resume        bool
+	retries       int
+	retryBackoff  time.Duration
 }
 
 func main() {
@@ -37,15 +54,38 @@ func main() {
 		log.Fatalf("\n❌ failed extractors initialisation: %v\n", err)
 	}
 
-	audit, err := NewAudit(config.checks, config.important, config.screenshotDir)
+	audit, err := NewAudit(auditOptions{
+		checks:        config.checks,
+		important:     config.important,
+		screenshotDir: config.screenshotDir,
+		concurrency:   config.concurrency,
+		serialLoad:    config.serialLoad,
+		crawlDepth:    config.crawlDepth,
+		crawlPages:    config.crawlPages,
+		exactURLs:     config.exactURLs,
+		throttle:      config.throttle,
+		budgetsFile:   config.budgets,
+		runs:          config.runs,
+		har:           config.har,
+		certExpiry:    config.certExpiry,
+		devices:       config.devices,
+		retries:       config.retries,
+		retryBackoff:  config.retryBackoff,
+	})
 	if err != nil {
 		log.Fatalf("\n❌ failed audit service initialisation: %v\n", err)
 	}
 
-	csvSink, err := NewCSVSink(config.output)
+	sink, err := NewSink(config.output, config.format)
 	if err != nil {
-		log.Fatalf("\n❌ failed csv output initialisation: %v\n", err)
+		log.Fatalf("\n❌ failed output initialisation: %v\n", err)
 	}
+
+	checkpoint, err := NewCheckpoint(config.checkpoint, config.resume)
+	if err != nil {
+		log.Fatalf("\n❌ failed checkpoint initialisation: %v\n", err)
+	}
+	audit.Resume(checkpoint.Completed())
 	spinner.Stop()
 
 	// collect websites from different sources
@@ -56,22 +96,55 @@ func main() {
 	}
 	spinner.Stop()
 
+	// checkpoint results as they're audited, and stream them if the sink supports it
+	streamSink, streaming := sink.(StreamSink)
+	onResult := func(res auditResult) {
+		err := checkpoint.WriteResult(res)
+		if err != nil {
+			fmt.Printf("⚠️ failed to checkpoint result for %s: %v\n", res.website, err)
+		}
+
+		if streaming {
+			err = streamSink.WriteResult(res)
+			if err != nil {
+				fmt.Printf("⚠️ failed to stream result for %s: %v\n", res.website, err)
+			}
+		}
+	}
+
 	// perform audits in a headless browser
 	spinner.Start("Auditing websites...")
-	audits, err := audit.Run(ctx, websites)
+	audits, err := audit.Run(ctx, websites, onResult)
 	if err != nil {
 		log.Fatalf("\n❌ failed website auditing: %v\n", err)
 	}
 	spinner.Stop()
 
-	// write audit results to csv
+	// write audit results to output
 	spinner.Start("Writing results...")
-	err = csvSink.WriteResults(audits)
+	err = sink.WriteResults(audits)
 	if err != nil {
 		log.Fatalf("\n❌ failed results writing: %v\n", err)
 	}
+
+	// run is complete, so there's nothing left to resume
+	err = checkpoint.Remove()
+	if err != nil {
+		fmt.Printf("⚠️ %v\n", err)
+	}
 	spinner.Stop()
 
+	// fail the run if any site is over budget, so it can gate CI pipelines
+	overBudget := 0
+	for _, res := range audits {
+		if !res.budgetsPassed() {
+			overBudget++
+		}
+	}
+	if overBudget > 0 {
+		log.Fatalf("\n❌ %d/%d results failed their budgets\n", overBudget, len(audits))
+	}
+
 	fmt.Println("✅ Done")
 }
 
@@ -83,10 +156,27 @@ func parseFlags() (*config, error) {
 	flag.StringVar(&config.search, "search", "", "Search prompt for which to find URLs from Google Places")
 	flag.StringVar(&config.scrape, "scrape", "", "Google input prompt to scrape URLs for")
 	flag.StringVar(&config.input, "input", "", "Path to input CSV file with URLs")
-	flag.StringVar(&config.output, "output", "report.csv", "Path to output CSV report")
-	flag.StringVar(&config.checks, "checks", "", "Comma-separated checks to run (security,lcp,console,request,headers,mobile,form,tech,screenshot). Empty = all checks")
+	flag.StringVar(&config.output, "output", "report.csv", "Path to output report")
+	flag.StringVar(&config.format, "format", "", "Output format (csv,json,jsonl,html). Empty = from output file extension, defaulting to csv")
+	flag.StringVar(&config.checks, "checks", "", fmt.Sprintf("Comma-separated checks to run (%s). Empty = all checks, except coverage", checkNames()))
 	flag.BoolVar(&config.important, "important", false, "Run only critical/important checks (faster)")
-	flag.StringVar(&config.screenshotDir, "screenshot-dir", "screenshots", "Path to folder to store screenshots")
+	flag.StringVar(&config.screenshotDir, "screenshot-dir", "screenshots", "Path to folder to store screenshots and HAR files")
+	flag.StringVar(&config.devices, "devices", "iphone13", "Comma-separated devices to audit each site on (iphone13,iphone12,pixel5,pixel7,galaxys9,ipad,ipadpro,desktop-1280,desktop-1440,desktop-1920 or custom WIDTHxHEIGHT)")
+	flag.StringVar(&config.throttle, "throttle", "none", fmt.Sprintf("Network and CPU throttling profile to load pages with (%s). slow-4g matches Lighthouse's mobile run", throttleNames()))
+	flag.IntVar(&config.concurrency, "concurrency", 1, "Number of sites to audit in parallel (each in its own browser tab)")
+	flag.BoolVar(&config.serialLoad, "serial-load", false, "Load one site at a time when auditing concurrently, so timing metrics (e.g. LCP) stay accurate")
+	flag.IntVar(&config.crawlDepth, "crawl-depth", 0, "How many links deep to crawl internal pages from each site's homepage (0 = homepage only)")
+	flag.IntVar(&config.crawlPages, "crawl-pages", 10, "Max number of pages to audit per site when crawling")
+	flag.BoolVar(&config.exactURLs, "exact-urls", false, "Audit the exact input URLs (path and query included) instead of each site's homepage - URLs on the same domain are grouped under it")
+
+	flag.IntVar(&config.runs, "runs", 1, "Number of times to load each page, reporting median, min, max and standard deviation of timing metrics - other checks run once (with coverage, pages are loaded once more)")
+	flag.BoolVar(&config.har, "har", false, "Export a HAR file per site, with every request made while loading its pages, to the screenshot folder")
+	flag.IntVar(&config.certExpiry, "cert-expiry", 30, "Number of days before expiry TLS certificates are flagged (tls check)")
+	flag.StringVar(&config.budgets, "budgets", "", "Path to YAML or JSON file with budgets (max values of metrics, e.g. lcp: 2500) to evaluate each site against - exits with a non-zero code if any budget fails")
+	flag.IntVar(&config.retries, "retries", 2, "Number of times to retry pages failing to load for transient reasons (e.g. timeouts, connection resets, 502/503/504)")
+	flag.DurationVar(&config.retryBackoff, "retry-backoff", 2*time.Second, "Delay before the first retry, doubled after each one")
+	flag.StringVar(&config.checkpoint, "checkpoint", "", "Path to checkpoint file storing completed results, to resume interrupted runs from. Empty = output path + \".checkpoint.jsonl\"")
+	flag.BoolVar(&config.resume, "resume", false, "Resume an interrupted run, skipping sites already audited in the checkpoint file")
 
 	flag.Parse()
 
@@ -94,6 +184,10 @@ func parseFlags() (*config, error) {
 		return nil, fmt.Errorf("unexpected arguments: %v", flag.Args())
 	}
 
+	if config.checkpoint == "" {
+		config.checkpoint = config.output + ".checkpoint.jsonl"
+	}
+
 	if config.search == "" && config.scrape == "" && config.input == "" {
 		return nil, fmt.Errorf("neither search prompt, nor scrape prompt, nor input file are specified")
 	}
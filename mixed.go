@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/chromedp/cdproto/audits"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/security"
+	"github.com/chromedp/chromedp"
+)
+
+const mixedContentName = "mixed" // check name, also keying its tracking state
+
+// resource types browsers treat as passive (optionally blockable) mixed
+// content - anything else can change the page, so is active and blocked
+var passiveMixedTypes = []string{"image", "media", "audio", "video", "favicon", "track"}
+
+// mixedResource is a subresource of an HTTPS page requested over HTTP
+type mixedResource struct {
+	URL    string `json:"url"` // insecure URL, as referenced by the page
+	Type   string `json:"type"`
+	Active bool   `json:"active"`
+	Status string `json:"status"` // "blocked", "upgraded" (to HTTPS by the browser) or "loaded"
+}
+
+// String formats the resource for output
+func (r mixedResource) String() string {
+	return fmt.Sprintf("%s (%s, %s)", r.URL, r.Type, r.Status)
+}
+
+// mixedContent holds the mixed content found on the page
+type mixedContent struct {
+	HTTPS   bool            `json:"https"` // mixed content only applies to HTTPS pages
+	Active  []mixedResource `json:"active"`
+	Passive []mixedResource `json:"passive"`
+}
+
+// mixedContentRecorder records mixed content issues reported by the browser
+type mixedContentRecorder struct {
+	mu     sync.Mutex
+	issues []audits.MixedContentIssueDetails
+}
+
+// startMixedContentTracking starts recording mixed content issues the browser
+// reports while the page loads, which include requests blocked before being sent
+func startMixedContentTracking(ctx context.Context, page *auditPage) error {
+	recorder := &mixedContentRecorder{}
+	chromedp.ListenTarget(ctx, func(ev any) {
+		issue, ok := ev.(*audits.EventIssueAdded)
+		if !ok || issue.Issue == nil || issue.Issue.Details == nil || issue.Issue.Details.MixedContentIssueDetails == nil {
+			return
+		}
+
+		recorder.mu.Lock()
+		recorder.issues = append(recorder.issues, *issue.Issue.Details.MixedContentIssueDetails)
+		recorder.mu.Unlock()
+	})
+	page.tracking[mixedContentName] = recorder
+
+	err := audits.Enable().Do(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to enable audits domain: %w", err)
+	}
+
+	return nil
+}
+
+// findMixedContent collects mixed content from issues reported by the browser,
+// along with HTTP requests made after the page's document was loaded - pages
+// loaded over HTTP (e.g. forced by the security check, for sites that don't
+// redirect to HTTPS) have none
+func findMixedContent(page *auditPage) (mixedContent, error) {
+	if page.response == nil || !strings.HasPrefix(page.response.URL, "https:") {
+		return mixedContent{}, nil
+	}
+
+	recorder, ok := page.tracking[mixedContentName].(*mixedContentRecorder)
+	if !ok {
+		return mixedContent{}, fmt.Errorf("mixed content issues weren't recorded")
+	}
+
+	recorder.mu.Lock()
+	issues := slices.Clone(recorder.issues)
+	recorder.mu.Unlock()
+
+	return newMixedContent(page.response.URL, issues, page.requests.Requests()), nil
+}
+
+// newMixedContent merges mixed content issues with insecure requests made by the
+// HTTPS page (the same resource may show up in both)
+func newMixedContent(documentURL string, issues []audits.MixedContentIssueDetails, requests []networkRequest) mixedContent {
+	mixed := mixedContent{HTTPS: true, Active: []mixedResource{}, Passive: []mixedResource{}}
+	seen := map[string]bool{}
+
+	add := func(resource mixedResource) {
+		key := strings.TrimPrefix(strings.TrimPrefix(resource.URL, "http://"), "https://")
+		if seen[key] {
+			return
+		}
+
+		seen[key] = true
+		if resource.Active {
+			mixed.Active = append(mixed.Active, resource)
+		} else {
+			mixed.Passive = append(mixed.Passive, resource)
+		}
+	}
+
+	for _, issue := range issues {
+		resourceType := strings.ToLower(string(issue.ResourceType))
+		resource := mixedResource{
+			URL:    issue.InsecureURL,
+			Type:   resourceType,
+			Active: !slices.Contains(passiveMixedTypes, resourceType),
+			Status: "loaded",
+		}
+
+		switch issue.ResolutionStatus {
+		case audits.MixedContentResolutionStatusMixedContentBlocked:
+			resource.Status = "blocked"
+		case audits.MixedContentResolutionStatusMixedContentAutomaticallyUpgraded:
+			resource.Status = "upgraded"
+		}
+
+		add(resource)
+	}
+
+	// requests before the page's document (e.g. redirects from HTTP) aren't subresources
+	start := slices.IndexFunc(requests, func(req networkRequest) bool {
+		return req.resourceType == network.ResourceTypeDocument && req.url == documentURL
+	})
+	if start < 0 {
+		return mixed
+	}
+
+	for _, req := range requests[start+1:] {
+		mixedType := req.request.MixedContentType
+		if !strings.HasPrefix(req.url, "http://") && (mixedType == "" || mixedType == security.MixedContentTypeNone) {
+			continue
+		}
+
+		resourceType := strings.ToLower(string(req.resourceType))
+		resource := mixedResource{
+			URL:    "http://" + strings.TrimPrefix(strings.TrimPrefix(req.url, "http://"), "https://"),
+			Type:   resourceType,
+			Active: mixedType == security.MixedContentTypeBlockable || !slices.Contains(passiveMixedTypes, resourceType),
+			Status: "loaded",
+		}
+
+		switch {
+		case req.blocked == network.BlockedReasonMixedContent:
+			resource.Status = "blocked"
+		case strings.HasPrefix(req.url, "https://"):
+			resource.Status = "upgraded"
+		}
+
+		add(resource)
+	}
+
+	return mixed
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+	"golang.org/x/net/publicsuffix"
+)
+
+// networkRecorder records the requests a page makes, from CDP network events,
+// so checks can inspect them once the page has loaded
+type networkRecorder struct {
+	mu       sync.Mutex
+	requests []*networkRequest
+	byID     map[network.RequestID]*networkRequest // latest request of each ID (redirects reuse it)
+}
+
+// networkRequest holds a single request made by the page
+type networkRequest struct {
+	url          string
+	resourceType network.ResourceType
+	request      *network.Request
+	response     *network.Response // missing if no response was received
+	transferred  int64             // bytes received over the network, headers included
+	decoded      int64             // decoded body bytes received
+	finished     bool
+	failed       bool
+	errorText    string
+	blocked      network.BlockedReason
+	sentAt       time.Time // when the request was sent
+	wallTime     time.Time // wall clock time the request was sent, for HAR export
+	finishedAt   time.Time // when loading finished or failed
+}
+
+// newNetworkRecorder creates a new networkRecorder instance
+func newNetworkRecorder() *networkRecorder {
+	return &networkRecorder{byID: map[network.RequestID]*networkRequest{}}
+}
+
+// listen starts recording network events of the page in the given context
+// (network domain must be enabled)
+func (r *networkRecorder) listen(ctx context.Context) {
+	chromedp.ListenTarget(ctx, r.handle)
+}
+
+// handle updates recorded requests from a single network event
+func (r *networkRecorder) handle(ev any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch ev := ev.(type) {
+	case *network.EventRequestWillBeSent:
+		// a redirect finishes the previous request with the same ID
+		if prev, ok := r.byID[ev.RequestID]; ok && ev.RedirectResponse != nil {
+			prev.response = ev.RedirectResponse
+			prev.transferred = int64(ev.RedirectResponse.EncodedDataLength)
+			prev.finished = true
+			prev.finishedAt = eventTime(ev.Timestamp)
+		}
+
+		req := &networkRequest{
+			url:          ev.Request.URL,
+			resourceType: ev.Type,
+			request:      ev.Request,
+			sentAt:       eventTime(ev.Timestamp),
+		}
+		if ev.WallTime != nil {
+			req.wallTime = ev.WallTime.Time()
+		}
+		r.requests = append(r.requests, req)
+		r.byID[ev.RequestID] = req
+	case *network.EventResponseReceived:
+		if req, ok := r.byID[ev.RequestID]; ok {
+			req.response = ev.Response
+			req.resourceType = ev.Type
+		}
+	case *network.EventDataReceived:
+		if req, ok := r.byID[ev.RequestID]; ok {
+			req.decoded += ev.DataLength
+		}
+	case *network.EventLoadingFinished:
+		if req, ok := r.byID[ev.RequestID]; ok {
+			req.transferred = int64(ev.EncodedDataLength)
+			req.finished = true
+			req.finishedAt = eventTime(ev.Timestamp)
+		}
+	case *network.EventLoadingFailed:
+		if req, ok := r.byID[ev.RequestID]; ok {
+			req.resourceType = ev.Type
+			req.failed = true
+			req.errorText = ev.ErrorText
+			req.blocked = ev.BlockedReason
+			req.finishedAt = eventTime(ev.Timestamp)
+		}
+	}
+}
+
+// Requests returns a snapshot of the requests recorded so far, in the order
+// they were sent - inline data and blob URLs aren't network requests, so
+// they're left out
+func (r *networkRecorder) Requests() []networkRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	requests := make([]networkRequest, 0, len(r.requests))
+	for _, req := range r.requests {
+		if strings.HasPrefix(req.url, "data:") || strings.HasPrefix(req.url, "blob:") {
+			continue
+		}
+
+		requests = append(requests, *req)
+	}
+
+	return requests
+}
+
+// eventTime converts an event timestamp, which may be missing
+func eventTime(timestamp *cdp.MonotonicTime) time.Time {
+	if timestamp == nil {
+		return time.Time{}
+	}
+
+	return timestamp.Time()
+}
+
+// navigationStart returns when the page's first document request was sent
+// (before any redirects), which the page's performance timings are relative to
+func (r *networkRecorder) navigationStart() (time.Time, bool) {
+	for _, req := range r.Requests() {
+		if req.resourceType == network.ResourceTypeDocument {
+			return req.sentAt, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// find returns the first request made to the given URL
+func (r *networkRecorder) find(requestURL string) (networkRequest, bool) {
+	for _, req := range r.Requests() {
+		if req.url == requestURL {
+			return req, true
+		}
+	}
+
+	return networkRequest{}, false
+}
+
+// isFirstParty reports whether the request URL belongs to the site with the
+// given domain, i.e. shares its registrable domain (e.g. cdn.example.co.uk
+// belongs to www.example.co.uk)
+func isFirstParty(domain, requestURL string) bool {
+	parsed, err := url.Parse(requestURL)
+	if err != nil {
+		return false
+	}
+
+	return registrableDomain(parsed.Hostname()) == registrableDomain(stripPort(domain))
+}
+
+// registrableDomain returns the host's domain directly below its public suffix,
+// falling back to the host itself (e.g. for IPs and localhost)
+func registrableDomain(host string) string {
+	host = strings.ToLower(host)
+
+	registrable, err := publicsuffix.EffectiveTLDPlusOne(host)
+	if err != nil {
+		return host
+	}
+
+	return registrable
+}
+
+// stripPort removes the port from a host, if it has one
+func stripPort(host string) string {
+	parsed := url.URL{Host: host}
+	return parsed.Hostname()
+}
@@ -0,0 +1,139 @@
+package main
+
+// template for the self-contained HTML report (no external assets, so it
+// works offline as a single file)
+const reportTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Site Audit Report</title>
+<style>
+	body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, sans-serif; margin: 0; padding: 24px; background: #f4f5f7; color: #1f2328; }
+	h1 { margin: 0 0 4px; }
+	.generated { color: #656d76; margin-bottom: 24px; }
+	#filter { width: 100%; max-width: 400px; padding: 8px; margin-bottom: 16px; border: 1px solid #d0d7de; border-radius: 6px; }
+	table { border-collapse: collapse; width: 100%; background: #fff; margin-bottom: 32px; }
+	th, td { text-align: left; padding: 8px 12px; border-bottom: 1px solid #d0d7de; }
+	th { cursor: pointer; user-select: none; background: #f6f8fa; }
+	th.asc::after { content: " ▲"; }
+	th.desc::after { content: " ▼"; }
+	.cards { display: grid; grid-template-columns: repeat(auto-fill, minmax(420px, 1fr)); gap: 24px; }
+	.card { background: #fff; border: 1px solid #d0d7de; border-radius: 8px; padding: 16px; overflow: hidden; }
+	.card h2 { margin: 0 0 8px; font-size: 1.2em; word-break: break-all; }
+	.stats { display: flex; gap: 16px; flex-wrap: wrap; margin-bottom: 12px; }
+	.stat { background: #f6f8fa; border-radius: 6px; padding: 6px 10px; }
+	.stat b { display: block; font-size: 1.2em; }
+	.screenshot { max-height: 480px; overflow-y: auto; border: 1px solid #d0d7de; margin-bottom: 12px; }
+	.screenshot img { width: 100%; display: block; }
+	.card h3 { font-size: 1em; margin: 12px 0 4px; }
+	.card ul { margin: 0; padding-left: 20px; }
+	.none { color: #656d76; }
+	.errors { color: #cf222e; }
+</style>
+</head>
+<body>
+<h1>Site Audit Report</h1>
+<div class="generated">Generated {{.Generated}} - {{len .Sites}} results{{if .Throttle}} - {{.Throttle}} throttling{{end}}</div>
+
+<input id="filter" type="search" placeholder="Filter sites...">
+
+<table id="summary">
+	<thead>
+		<tr>
+			<th data-type="text">Website</th>
+			{{if .ShowDevice}}<th data-type="text">Device</th>{{end}}
+			<th data-type="number">Issues</th>
+			{{if .ShowLCP}}<th data-type="number">LCP (ms)</th>{{end}}
+			{{if .ShowScore}}<th data-type="number">Responsive Score</th>{{end}}
+			{{if .ShowSecure}}<th data-type="text">Secure</th>{{end}}
+			<th data-type="number">Errors</th>
+			<th data-type="text">Failure</th>
+			{{if .ShowBudget}}<th data-type="text">Budgets</th>{{end}}
+		</tr>
+	</thead>
+	<tbody>
+	{{range .Sites}}
+		<tr data-site="{{.ID}}">
+			<td><a href="#{{.ID}}">{{.Website}}</a></td>
+			{{if $.ShowDevice}}<td>{{.Device}}</td>{{end}}
+			<td>{{.Issues}}</td>
+			{{if $.ShowLCP}}<td>{{.LCP}}</td>{{end}}
+			{{if $.ShowScore}}<td>{{.Score}}</td>{{end}}
+			{{if $.ShowSecure}}<td>{{.Secure}}</td>{{end}}
+			<td>{{len .Errors}}</td>
+			<td>{{.Failure}}</td>
+			{{if $.ShowBudget}}<td>{{.Verdict}}</td>{{end}}
+		</tr>
+	{{end}}
+	</tbody>
+</table>
+
+<div class="cards">
+{{range .Sites}}
+	<div class="card" id="{{.ID}}">
+		<h2>{{.Website}}{{if $.ShowDevice}} ({{.Device}}){{end}}</h2>
+		<div class="stats">
+			<div class="stat"><b>{{.Issues}}</b>issues</div>
+			{{if .LCP}}<div class="stat"><b>{{.LCP}} ms</b>LCP</div>{{end}}
+			{{if .Score}}<div class="stat"><b>{{.Score}}</b>responsive score</div>{{end}}
+			{{if .Secure}}<div class="stat"><b>{{.Secure}}</b>HTTPS</div>{{end}}
+			{{if .Failure}}<div class="stat errors"><b>{{.Failure}}</b>failure</div>{{end}}
+			{{if .Verdict}}<div class="stat{{if eq .Verdict "fail"}} errors{{end}}"><b>{{.Verdict}}</b>budgets</div>{{end}}
+		</div>
+		{{if .Screenshot}}<div class="screenshot"><img src="{{.Screenshot}}" alt="Screenshot of {{.Website}}"></div>{{end}}
+		{{if .HAR}}<p><a href="{{.HAR}}" download>Download HAR file</a></p>{{end}}
+		{{range .Sections}}
+			<h3>{{.Title}}</h3>
+			{{if .Values}}<ul>{{range .Values}}<li>{{.}}</li>{{end}}</ul>{{else}}<div class="none">None</div>{{end}}
+		{{end}}
+		{{if .Budgets}}
+			<h3>Budgets</h3>
+			<ul>{{range .Budgets}}<li>{{.}}</li>{{end}}</ul>
+		{{end}}
+		{{if .Pages}}
+			<h3>Audited Pages</h3>
+			<ul>{{range .Pages}}<li>{{.URL}} - {{.Issues}} issues</li>{{end}}</ul>
+		{{end}}
+		{{if .Errors}}
+			<h3>Audit Errors</h3>
+			<ul class="errors">{{range .Errors}}<li>{{.}}</li>{{end}}</ul>
+		{{end}}
+	</div>
+{{end}}
+</div>
+
+<script>
+	// filter summary rows and cards by text
+	document.getElementById('filter').addEventListener('input', (e) => {
+		const query = e.target.value.toLowerCase();
+		document.querySelectorAll('#summary tbody tr').forEach((row) => {
+			const match = row.textContent.toLowerCase().includes(query);
+			row.style.display = match ? '' : 'none';
+			document.getElementById(row.dataset.site).style.display = match ? '' : 'none';
+		});
+	});
+
+	// sort summary rows by clicked column
+	document.querySelectorAll('#summary th').forEach((th, index) => {
+		th.addEventListener('click', () => {
+			const asc = !th.classList.contains('asc');
+			document.querySelectorAll('#summary th').forEach((h) => h.classList.remove('asc', 'desc'));
+			th.classList.add(asc ? 'asc' : 'desc');
+
+			const tbody = document.querySelector('#summary tbody');
+			const rows = Array.from(tbody.querySelectorAll('tr'));
+			rows.sort((a, b) => {
+				const x = a.children[index].textContent.trim();
+				const y = b.children[index].textContent.trim();
+				const cmp = th.dataset.type === 'number' ?
+					(parseFloat(x) || 0) - (parseFloat(y) || 0) : x.localeCompare(y);
+				return asc ? cmp : -cmp;
+			});
+			rows.forEach((row) => tbody.appendChild(row));
+		});
+	});
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// retryPolicy decides how many times, and how long apart, pages failing
+// to load are attempted
+type retryPolicy struct {
+	maxAttempts int           // total attempts, including the first one
+	backoff     time.Duration // delay before the first retry, doubled after each
+}
+
+// delay returns how long to wait before the next attempt, after the given one
+func (p retryPolicy) delay(attempt int) time.Duration {
+	return p.backoff * time.Duration(1<<(attempt-1))
+}
+
+// httpStatusError is returned when the page's main document request fails
+type httpStatusError struct {
+	status int64
+}
+
+// Error returns the error message
+func (e httpStatusError) Error() string {
+	return fmt.Sprintf("HTTP Status - %d", e.status)
+}
+
+// HTTP statuses usually returned by overloaded or restarting servers
+var transientStatuses = []int64{502, 503, 504}
+
+// browser network errors caused by temporary conditions - permanent ones
+// (e.g. net::ERR_NAME_NOT_RESOLVED for a non-existent domain) aren't retried
+var transientNetErrors = []string{
+	"net::ERR_NAME_RESOLUTION_FAILED", "net::ERR_CONNECTION_RESET",
+	"net::ERR_CONNECTION_CLOSED", "net::ERR_CONNECTION_ABORTED",
+	"net::ERR_CONNECTION_TIMED_OUT", "net::ERR_TIMED_OUT",
+	"net::ERR_EMPTY_RESPONSE", "net::ERR_NETWORK_CHANGED",
+	"net::ERR_INTERNET_DISCONNECTED", "net::ERR_ADDRESS_UNREACHABLE",
+	"net::ERR_HTTP2_PROTOCOL_ERROR", "net::ERR_QUIC_PROTOCOL_ERROR",
+}
+
+// isTransientErr reports whether the page load failure is likely to pass
+// on another attempt
+func isTransientErr(err error) bool {
+	if classifyFailure(err) == failureTimeout {
+		return true
+	}
+
+	var statusErr httpStatusError
+	if errors.As(err, &statusErr) {
+		return slices.Contains(transientStatuses, statusErr.status)
+	}
+
+	for _, netErr := range transientNetErrors {
+		if strings.Contains(err.Error(), netErr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// runPageWithRetry audits a single page, retrying transient load failures
+// with exponential backoff, each time in a fresh tab
+func (a *Audit) runPageWithRetry(
+	ctx context.Context,
+	website *Website,
+	device deviceProfile,
+	pageURL string,
+	checks []Check,
+	discoverLinks bool,
+) (pageResult, []string) {
+	for attempt := 1; ; attempt++ {
+		result, links, err := a.runPage(ctx, website, device, pageURL, checks, discoverLinks)
+		result.attempts = attempt
+
+		result.failure = classifyFailure(err)
+		if result.failure == failureNone && len(result.checkErrs) > 0 {
+			result.failure = failureCheckScript
+		}
+
+		if err == nil || attempt >= a.retry.maxAttempts || !isTransientErr(err) {
+			return result, links
+		}
+
+		delay := a.retry.delay(attempt)
+		fmt.Printf("⚠️ %s: retrying in %s (%v)\n", pageURL, delay, err)
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return result, links
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// timing metrics sampled across runs, in output order
+var timingMetrics = []string{
+	"lcp", "cls", "fcp", "ttfb", "tbt", "domContentLoaded", "load", "longtasks", "longestTask", "blocking",
+}
+
+// output labels of timing metrics, by name
+var timingLabels = map[string]string{
+	"lcp": "LCP", "cls": "CLS", "fcp": "FCP", "ttfb": "TTFB", "tbt": "TBT",
+	"domContentLoaded": "DOMContentLoaded", "load": "Load",
+	"longtasks": "Long Tasks", "longestTask": "Longest Task", "blocking": "Render-Blocking Delay",
+}
+
+// timingStats summarises a timing metric sampled across runs
+type timingStats struct {
+	Samples int     `json:"samples"`
+	Median  float64 `json:"median"`
+	Min     float64 `json:"min"`
+	Max     float64 `json:"max"`
+	StdDev  float64 `json:"stdDev"`
+}
+
+// newTimingStats calculates the stats of the given samples
+func newTimingStats(samples []float64) timingStats {
+	sorted := slices.Sorted(slices.Values(samples))
+	n := len(sorted)
+	stats := timingStats{Samples: n, Min: sorted[0], Max: sorted[n-1]}
+
+	if n%2 == 1 {
+		stats.Median = sorted[n/2]
+	} else {
+		stats.Median = (sorted[n/2-1] + sorted[n/2]) / 2
+	}
+
+	// sample standard deviation
+	if n > 1 {
+		mean := 0.0
+		for _, v := range sorted {
+			mean += v
+		}
+		mean /= float64(n)
+
+		variance := 0.0
+		for _, v := range sorted {
+			variance += (v - mean) * (v - mean)
+		}
+		stats.StdDev = math.Sqrt(variance / float64(n-1))
+	}
+
+	return stats
+}
+
+// String formats the stats for output
+func (s timingStats) String() string {
+	return fmt.Sprintf("median %s (min %s, max %s, stddev %s, n=%d)",
+		formatStat(s.Median), formatStat(s.Min), formatStat(s.Max), formatStat(s.StdDev), s.Samples)
+}
+
+// formatStat formats a stat value, keeping decimals for small values (e.g. CLS)
+func formatStat(value float64) string {
+	if value < 10 {
+		return fmt.Sprintf("%.3f", value)
+	}
+
+	return fmt.Sprintf("%.0f", value)
+}
+
+// sampledMetrics returns the timing metrics sampled on any of the results' pages,
+// in output order
+func sampledMetrics(results []auditResult) []string {
+	sampled := map[string]bool{}
+	for _, res := range results {
+		for _, page := range res.pages {
+			for metric := range page.timings {
+				sampled[metric] = true
+			}
+		}
+	}
+
+	metrics := []string{}
+	for _, metric := range timingMetrics {
+		if sampled[metric] {
+			metrics = append(metrics, metric)
+		}
+	}
+
+	return metrics
+}
+
+// runPageSamples audits a single page and, when more than one run is set, loads
+// it again in fresh tabs re-running only checks that measure timings - their
+// metrics are then summarised across these runs. The first run counts as a
+// sample unless an opt-in check (e.g. coverage) slowed its load down, in which
+// case an extra clean run takes its place
+func (a *Audit) runPageSamples(
+	ctx context.Context,
+	website *Website,
+	device deviceProfile,
+	pageURL string,
+	discoverLinks bool,
+) (pageResult, []string) {
+	result, links := a.runPageWithRetry(ctx, website, device, pageURL, a.checks, discoverLinks)
+	if a.runs == 1 || (result.failure != failureNone && result.failure != failureCheckScript) {
+		return result, links
+	}
+
+	sampledChecks := []Check{}
+	for _, check := range a.checks {
+		if check.Sampled() {
+			sampledChecks = append(sampledChecks, check)
+		}
+	}
+	if len(sampledChecks) == 0 {
+		return result, links
+	}
+
+	samples := map[string][]float64{}
+	first := 2
+	if slices.ContainsFunc(a.checks, Check.OptIn) {
+		first = 1
+	} else {
+		addSamples(samples, sampledChecks, result)
+	}
+
+	for run := first; run <= a.runs; run++ {
+		fmt.Printf("\r   - sampling page %s (run %d/%d)\n", pageURL, run, a.runs)
+
+		sample, _ := a.runPageWithRetry(ctx, website, device, pageURL, sampledChecks, false)
+		if sample.failure != failureNone {
+			fmt.Printf("⚠️ %s: skipping failed run %d/%d (%s)\n", pageURL, run, a.runs, sample.failure)
+			continue
+		}
+
+		addSamples(samples, sampledChecks, sample)
+	}
+
+	result.timings = map[string]timingStats{}
+	for name, values := range samples {
+		result.timings[name] = newTimingStats(values)
+	}
+
+	return result, links
+}
+
+// addSamples adds the timing metrics measured by a single run to the samples
+func addSamples(samples map[string][]float64, checks []Check, run pageResult) {
+	for _, check := range checks {
+		for name, value := range check.Timings(run.results[check.Name()]) {
+			samples[name] = append(samples[name], value)
+		}
+	}
+}
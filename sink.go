@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Sink defines the interface for writing audit results to different outputs
+// - it represents the destination end of the audit data flow
+type Sink interface {
+	Name() string // makes debugging easier
+	WriteResults(results []auditResult) error
+}
+
+// StreamSink is implemented by sinks that can also write each result as soon
+// as it's audited, before all results are written at the end
+type StreamSink interface {
+	Sink
+	WriteResult(result auditResult) error
+}
+
+// NewSink is a factory function to initialise the output sink, based on the
+// specified format or, if empty, the output file extension (defaulting to CSV)
+func NewSink(outputFile, format string) (Sink, error) {
+	if format == "" {
+		format = "csv"
+
+		switch strings.ToLower(filepath.Ext(outputFile)) {
+		case ".json":
+			format = "json"
+		case ".jsonl":
+			format = "jsonl"
+		case ".html", ".htm":
+			format = "html"
+		}
+	}
+
+	switch format {
+	case "json":
+		jsonSink, err := NewJSONSink(outputFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialise json sink: %w", err)
+		}
+		return jsonSink, nil
+	case "jsonl":
+		jsonlSink, err := NewJSONLSink(outputFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialise jsonl sink: %w", err)
+		}
+		return jsonlSink, nil
+	case "html":
+		htmlSink, err := NewHTMLSink(outputFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialise html sink: %w", err)
+		}
+		return htmlSink, nil
+	case "csv":
+		csvSink, err := NewCSVSink(outputFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialise csv sink: %w", err)
+		}
+		return csvSink, nil
+	default:
+		return nil, fmt.Errorf("unknown output format: %s", format)
+	}
+}
+
+// createOutputFile ensures the output directory exists and is writable
+func createOutputFile(outputFile string) error {
+	if outputFile == "" {
+		return fmt.Errorf("output path cannot be empty")
+	}
+
+	// create the output file
+	// this validates both directory existence and write permissions
+	file, err := os.Create(outputFile)
+	if err != nil {
+		return fmt.Errorf("cannot create output file %s: %w", outputFile, err)
+	}
+	file.Close()
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+// third parties keeping the main thread busy for longer than this are
+// reported as issues (Lighthouse's third-party blocking time threshold)
+const heavyThirdPartyMs = 250
+
+// thirdParty holds the impact of a single third party on the page
+type thirdParty struct {
+	Name         string         `json:"name"` // vendor name, or registrable domain if unknown
+	Category     vendorCategory `json:"category"`
+	Requests     int            `json:"requests"`
+	Bytes        int64          `json:"bytes"`
+	MainThreadMs float64        `json:"mainThreadMs"`
+}
+
+// String formats the third party for output
+func (t thirdParty) String() string {
+	return fmt.Sprintf("%s (%s) - %d requests, %s, %.0f ms main thread",
+		t.Name, t.Category, t.Requests, formatBytes(t.Bytes), t.MainThreadMs)
+}
+
+// thirdPartyImpact groups requests made to third parties by vendor, along
+// with main thread time of their scripts - heaviest first
+func thirdPartyImpact(domain string, requests []networkRequest, scriptTime map[string]float64) []thirdParty {
+	byName := map[string]*thirdParty{}
+	parties := []*thirdParty{}
+
+	find := func(requestURL string) *thirdParty {
+		name, category := identifyThirdParty(requestURL)
+		party, ok := byName[name]
+		if !ok {
+			party = &thirdParty{Name: name, Category: category}
+			byName[name] = party
+			parties = append(parties, party)
+		}
+
+		return party
+	}
+
+	for _, req := range requests {
+		if isFirstParty(domain, req.url) {
+			continue
+		}
+
+		party := find(req.url)
+		party.Requests++
+		party.Bytes += req.transferred
+	}
+
+	for scriptURL, ms := range scriptTime {
+		if scriptURL == "inline" || isFirstParty(domain, scriptURL) {
+			continue
+		}
+
+		find(scriptURL).MainThreadMs += ms
+	}
+
+	impact := []thirdParty{}
+	for _, party := range parties {
+		impact = append(impact, *party)
+	}
+
+	slices.SortStableFunc(impact, func(a, b thirdParty) int {
+		return cmp.Or(cmp.Compare(b.MainThreadMs, a.MainThreadMs), cmp.Compare(b.Bytes, a.Bytes))
+	})
+
+	return impact
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+const (
+	basePageTimeout   = 60 * time.Second // time each page gets to load and be audited
+	maxThrottledBytes = 20 * 1024 * 1024 // page size throttled loads are given extra time to download
+)
+
+// throttleProfile is a network and CPU throttling profile applied while
+// loading pages, to approximate real mobile conditions
+type throttleProfile struct {
+	name         string
+	latency      float64 // added request latency (ms)
+	downloadKbps float64
+	uploadKbps   float64
+	cpuSlowdown  float64 // CPU slowdown multiplier (1 = no slowdown)
+}
+
+// throttlePresets maps profile names accepted by the -throttle flag to their settings
+var throttlePresets = map[string]throttleProfile{
+	"none": {name: "none", cpuSlowdown: 1},
+	// WebPageTest's "3G Fast" connection
+	"fast-3g": {name: "fast-3g", latency: 150, downloadKbps: 1600, uploadKbps: 768, cpuSlowdown: 4},
+	// Lighthouse's mobile run (150ms RTT, 1.6Mbps, scaled like its devtools throttling)
+	"slow-4g": {name: "slow-4g", latency: 562.5, downloadKbps: 1474.56, uploadKbps: 675, cpuSlowdown: 4},
+}
+
+// parseThrottle returns the throttling profile with the given name
+func parseThrottle(name string) (throttleProfile, error) {
+	profile, ok := throttlePresets[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return throttleProfile{}, fmt.Errorf("unknown throttling profile: %s", name)
+	}
+
+	return profile, nil
+}
+
+// throttleNames returns the comma-separated names of all throttling profiles
+func throttleNames() string {
+	return strings.Join(slices.Sorted(maps.Keys(throttlePresets)), ",")
+}
+
+// enabled reports whether the profile throttles anything
+func (p throttleProfile) enabled() bool {
+	return p.latency > 0 || p.downloadKbps > 0 || p.uploadKbps > 0 || p.cpuSlowdown > 1
+}
+
+// pageTimeout returns how long each page gets to load and be audited - with
+// throttled throughput, a heavy page alone can take over a minute to download,
+// so extra time is given for it
+func (p throttleProfile) pageTimeout() time.Duration {
+	if p.downloadKbps <= 0 {
+		return basePageTimeout
+	}
+
+	bytesPerSecond := p.downloadKbps * 1024 / 8
+	return basePageTimeout + time.Duration(maxThrottledBytes/bytesPerSecond*float64(time.Second)).Round(time.Second)
+}
+
+// apply returns a chromedp.Action that emulates the profile's network
+// conditions and CPU slowdown in the current tab (network domain must be enabled)
+func (p throttleProfile) apply() chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		if !p.enabled() {
+			return nil
+		}
+
+		// throughput is set in bytes per second, where -1 disables throttling -
+		// kbps are converted as 1024 bits, like Lighthouse does
+		download, upload := -1.0, -1.0
+		if p.downloadKbps > 0 {
+			download = p.downloadKbps * 1024 / 8
+		}
+		if p.uploadKbps > 0 {
+			upload = p.uploadKbps * 1024 / 8
+		}
+
+		err := network.EmulateNetworkConditions(false, p.latency, download, upload).Do(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to emulate network conditions: %w", err)
+		}
+
+		err = emulation.SetCPUThrottlingRate(p.cpuSlowdown).Do(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to throttle CPU: %w", err)
+		}
+
+		return nil
+	})
+}
@@ -26,3 +26,13 @@ func isIgnoredResource(resource string, ignoredPatterns []string) bool {
 
 	return false
 }
+
+// boolToEmoji takes in a boolean and returns corresponding
+// emoji to visual inspection
+func boolToEmoji(ok bool) string {
+	if !ok {
+		return "❌"
+	}
+
+	return "✅"
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"net/url"
+	"strings"
+)
+
+// vendorCategory groups third party vendors by what they're used for
+type vendorCategory string
+
+const (
+	categoryAnalytics  vendorCategory = "analytics"
+	categoryAds        vendorCategory = "ads"
+	categoryChat       vendorCategory = "chat"
+	categorySocial     vendorCategory = "social"
+	categoryCDN        vendorCategory = "cdn"
+	categoryFonts      vendorCategory = "fonts"
+	categoryTagManager vendorCategory = "tag-manager"
+	categoryUnknown    vendorCategory = "unknown"
+)
+
+// vendor is a known third party, matched by patterns in its request URLs
+type vendor struct {
+	name         string
+	category     vendorCategory
+	patterns     []string
+	idlePatterns []string // patterns of its requests that aren't waited for when checking if the page is idle
+}
+
+// vendors lists known third parties - the first vendor with a pattern
+// contained in a request URL is matched
+var vendors = []vendor{
+	// analytics
+	{name: "Google Analytics", category: categoryAnalytics, patterns: []string{"google-analytics.com", "google.com/gen_204"}, idlePatterns: []string{"google-analytics.com", "google.com/gen_204"}},
+	{name: "Hotjar", category: categoryAnalytics, patterns: []string{"hotjar.com", "hotjar.io"}, idlePatterns: []string{"hotjar.com"}},
+	{name: "Microsoft Clarity", category: categoryAnalytics, patterns: []string{"clarity.ms"}},
+	{name: "Scorecard Research", category: categoryAnalytics, patterns: []string{"scorecardresearch.com"}, idlePatterns: []string{"scorecardresearch.com"}},
+	{name: "New Relic", category: categoryAnalytics, patterns: []string{"newrelic.com", "nr-data.net"}, idlePatterns: []string{"newrelic.com"}},
+	{name: "Cloudflare Insights", category: categoryAnalytics, patterns: []string{"cloudflareinsights.com"}, idlePatterns: []string{"cloudflareinsights.com"}},
+	{name: "Segment", category: categoryAnalytics, patterns: []string{"segment.io", "segment.com"}, idlePatterns: []string{"segment.io"}},
+	{name: "Sentry", category: categoryAnalytics, patterns: []string{"sentry.io", "sentry-cdn.com"}, idlePatterns: []string{"sentry.io"}},
+	{name: "Shopify Analytics", category: categoryAnalytics, patterns: []string{"monorail-edge.shopify.com", "shopifycloud.com"}, idlePatterns: []string{"monorail-edge.shopify.com", "shopifycloud.com"}},
+	{name: "Mixpanel", category: categoryAnalytics, patterns: []string{"mixpanel.com"}},
+	{name: "Heap", category: categoryAnalytics, patterns: []string{"heapanalytics.com"}},
+	{name: "Plausible", category: categoryAnalytics, patterns: []string{"plausible.io"}},
+
+	// ads and conversion pixels
+	{name: "Google Ads", category: categoryAds, patterns: []string{"doubleclick.net", "googlesyndication.com", "googleadservices.com"}, idlePatterns: []string{"doubleclick.net", "googlesyndication.com"}},
+	{name: "Amazon Ads", category: categoryAds, patterns: []string{"amazon-adsystem.com", "adsystem.amazon"}, idlePatterns: []string{"amazon-adsystem.com", "adsystem.amazon"}},
+	{name: "Facebook Pixel", category: categoryAds, patterns: []string{"facebook.com/tr"}, idlePatterns: []string{"facebook.com/tr"}},
+	{name: "LinkedIn Insight", category: categoryAds, patterns: []string{"linkedin.com/px", "snap.licdn.com"}, idlePatterns: []string{"linkedin.com/px"}},
+	{name: "Twitter Ads", category: categoryAds, patterns: []string{"twitter.com/i/adsct", "static.ads-twitter.com"}, idlePatterns: []string{"twitter.com/i/adsct"}},
+	{name: "Pinterest Tag", category: categoryAds, patterns: []string{"pinterest.com/ct", "s.pinimg.com/ct"}, idlePatterns: []string{"pinterest.com/ct"}},
+	{name: "TikTok Pixel", category: categoryAds, patterns: []string{"tiktok.com/i18n", "analytics.tiktok.com"}, idlePatterns: []string{"tiktok.com/i18n"}},
+	{name: "Snapchat Pixel", category: categoryAds, patterns: []string{"snapchat.com/p", "sc-static.net"}, idlePatterns: []string{"snapchat.com/p"}},
+	{name: "Criteo", category: categoryAds, patterns: []string{"criteo.com", "criteo.net"}},
+	{name: "Taboola", category: categoryAds, patterns: []string{"taboola.com"}},
+	{name: "Outbrain", category: categoryAds, patterns: []string{"outbrain.com"}},
+
+	// chat and support widgets
+	{name: "Intercom", category: categoryChat, patterns: []string{"intercom.io", "intercomcdn.com"}, idlePatterns: []string{"intercom.io"}},
+	{name: "Zendesk", category: categoryChat, patterns: []string{"zendesk.com", "zdassets.com"}, idlePatterns: []string{"zendesk.com"}},
+	{name: "Drift", category: categoryChat, patterns: []string{"drift.com", "driftt.com"}, idlePatterns: []string{"drift.com"}},
+	{name: "Crisp", category: categoryChat, patterns: []string{"crisp.chat"}, idlePatterns: []string{"crisp.chat"}},
+	{name: "Tawk.to", category: categoryChat, patterns: []string{"tawk.to"}, idlePatterns: []string{"tawk.to"}},
+	{name: "LiveChat", category: categoryChat, patterns: []string{"livechat.com", "livechatinc.com"}, idlePatterns: []string{"livechat.com"}},
+	{name: "Freshchat", category: categoryChat, patterns: []string{"freshchat.com"}, idlePatterns: []string{"freshchat.com"}},
+	{name: "Help Scout", category: categoryChat, patterns: []string{"helpscout.net"}, idlePatterns: []string{"helpscout.net"}},
+	{name: "Olark", category: categoryChat, patterns: []string{"olark.com"}, idlePatterns: []string{"olark.com"}},
+	{name: "LivePerson", category: categoryChat, patterns: []string{"liveperson.net"}, idlePatterns: []string{"liveperson.net"}},
+	{name: "Pusher", category: categoryChat, patterns: []string{"pusher.com"}, idlePatterns: []string{"pusher.com"}},
+	{name: "Tidio", category: categoryChat, patterns: []string{"tidio.co"}},
+
+	// social embeds and SDKs
+	{name: "Facebook", category: categorySocial, patterns: []string{"facebook.net", "facebook.com"}, idlePatterns: []string{"facebook.net"}},
+	{name: "Twitter", category: categorySocial, patterns: []string{"platform.twitter.com", "twimg.com"}},
+	{name: "LinkedIn", category: categorySocial, patterns: []string{"platform.linkedin.com"}},
+	{name: "Instagram", category: categorySocial, patterns: []string{"instagram.com", "cdninstagram.com"}},
+	{name: "YouTube", category: categorySocial, patterns: []string{"youtube.com", "ytimg.com", "youtube-nocookie.com"}},
+
+	// tag managers
+	{name: "Google Tag Manager", category: categoryTagManager, patterns: []string{"googletagmanager.com"}, idlePatterns: []string{"googletagmanager.com"}},
+	{name: "Tealium", category: categoryTagManager, patterns: []string{"tiqcdn.com"}},
+	{name: "Adobe Launch", category: categoryTagManager, patterns: []string{"assets.adobedtm.com"}},
+
+	// fonts
+	{name: "Google Fonts", category: categoryFonts, patterns: []string{"fonts.googleapis.com", "fonts.gstatic.com"}},
+	{name: "Adobe Fonts", category: categoryFonts, patterns: []string{"use.typekit.net", "p.typekit.net"}},
+	{name: "Font Awesome", category: categoryFonts, patterns: []string{"fontawesome.com"}},
+
+	// CDNs
+	{name: "cdnjs", category: categoryCDN, patterns: []string{"cdnjs.cloudflare.com"}},
+	{name: "jsDelivr", category: categoryCDN, patterns: []string{"cdn.jsdelivr.net"}},
+	{name: "unpkg", category: categoryCDN, patterns: []string{"unpkg.com"}},
+	{name: "Google Hosted Libraries", category: categoryCDN, patterns: []string{"ajax.googleapis.com"}},
+	{name: "jQuery CDN", category: categoryCDN, patterns: []string{"code.jquery.com"}},
+	{name: "Amazon CloudFront", category: categoryCDN, patterns: []string{"cloudfront.net"}},
+	{name: "Akamai", category: categoryCDN, patterns: []string{"akamaihd.net", "akamaized.net"}},
+}
+
+// matchVendor finds the known vendor a request URL belongs to
+func matchVendor(requestURL string) (vendor, bool) {
+	lowerURL := strings.ToLower(requestURL)
+	for _, v := range vendors {
+		for _, pattern := range v.patterns {
+			if strings.Contains(lowerURL, pattern) {
+				return v, true
+			}
+		}
+	}
+
+	return vendor{}, false
+}
+
+// identifyThirdParty returns the name and category of the third party a request
+// URL belongs to - unknown ones are named by their registrable domain
+func identifyThirdParty(requestURL string) (string, vendorCategory) {
+	if v, ok := matchVendor(requestURL); ok {
+		return v.name, v.category
+	}
+
+	parsed, err := url.Parse(requestURL)
+	if err != nil {
+		return requestURL, categoryUnknown
+	}
+
+	return registrableDomain(parsed.Hostname()), categoryUnknown
+}
+
+// patterns to ignore during idle check (analytics, tracking, chats, favicons)
+var ignoredIdlePatterns = idlePatterns()
+
+// idlePatterns collects the patterns of vendors ignored during the idle check,
+// along with generic tracking and favicon patterns
+func idlePatterns() []string {
+	patterns := []string{"favicon.ico", "analytics", "telemetry"}
+	for _, v := range vendors {
+		patterns = append(patterns, v.idlePatterns...)
+	}
+
+	return patterns
+}
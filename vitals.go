@@ -0,0 +1,129 @@
+package main
+
+import "fmt"
+
+// vitalRating classifies a metric value using its published thresholds
+type vitalRating string
+
+const (
+	ratingNone             vitalRating = "" // metric wasn't measured
+	ratingGood             vitalRating = "good"
+	ratingNeedsImprovement vitalRating = "needs-improvement"
+	ratingPoor             vitalRating = "poor"
+)
+
+// vitalThresholds holds the upper bounds of a metric's "good" and
+// "needs improvement" ratings
+type vitalThresholds struct {
+	good float64
+	poor float64 // values above are rated poor
+}
+
+// published thresholds (web.dev for Core Web Vitals, Lighthouse mobile for TBT)
+var (
+	lcpThresholds  = vitalThresholds{good: 2500, poor: 4000}
+	clsThresholds  = vitalThresholds{good: 0.1, poor: 0.25}
+	fcpThresholds  = vitalThresholds{good: 1800, poor: 3000}
+	ttfbThresholds = vitalThresholds{good: 800, poor: 1800}
+	tbtThresholds  = vitalThresholds{good: 200, poor: 600}
+)
+
+// rate returns the rating of the given value
+func (t vitalThresholds) rate(value float64) vitalRating {
+	switch {
+	case value <= t.good:
+		return ratingGood
+	case value <= t.poor:
+		return ratingNeedsImprovement
+	default:
+		return ratingPoor
+	}
+}
+
+// rateTiming returns the rating of the given timing, where a zero
+// timing means it wasn't measured
+func (t vitalThresholds) rateTiming(ms float64) vitalRating {
+	if ms <= 0 {
+		return ratingNone
+	}
+
+	return t.rate(ms)
+}
+
+// vitalMetric holds a metric value along with its rating
+type vitalMetric struct {
+	Value  float64     `json:"value"`
+	Rating vitalRating `json:"rating"`
+}
+
+// webVitals holds the page's Core Web Vitals (other than LCP, which has its own
+// check) and paint timings, in milliseconds (except CLS) - INP needs real user
+// interactions, so total blocking time is collected as its lab proxy
+type webVitals struct {
+	CLS              vitalMetric `json:"cls"`
+	FCP              vitalMetric `json:"fcp"`
+	TTFB             vitalMetric `json:"ttfb"`
+	TBT              vitalMetric `json:"tbt"`
+	DOMContentLoaded float64     `json:"domContentLoaded"` // no published thresholds
+	Load             float64     `json:"load"`             // no published thresholds
+}
+
+// newWebVitals rates raw metric values collected from the page
+func newWebVitals(cls, fcp, ttfb, tbt, domContentLoaded, load float64) webVitals {
+	return webVitals{
+		CLS:              vitalMetric{Value: cls, Rating: clsThresholds.rate(cls)},
+		FCP:              vitalMetric{Value: fcp, Rating: fcpThresholds.rateTiming(fcp)},
+		TTFB:             vitalMetric{Value: ttfb, Rating: ttfbThresholds.rateTiming(ttfb)},
+		TBT:              vitalMetric{Value: tbt, Rating: tbtThresholds.rate(tbt)},
+		DOMContentLoaded: domContentLoaded,
+		Load:             load,
+	}
+}
+
+// metrics returns the rated metrics, in output order
+func (v webVitals) metrics() []vitalMetric {
+	return []vitalMetric{v.CLS, v.FCP, v.TTFB, v.TBT}
+}
+
+// vitalsTimings returns the measured vitals and timings, by metric name
+func vitalsTimings(vitals webVitals) map[string]float64 {
+	timings := map[string]float64{}
+	for name, metric := range map[string]vitalMetric{
+		"cls": vitals.CLS, "fcp": vitals.FCP, "ttfb": vitals.TTFB, "tbt": vitals.TBT,
+	} {
+		if metric.Rating != ratingNone {
+			timings[name] = metric.Value
+		}
+	}
+
+	if vitals.DOMContentLoaded > 0 {
+		timings["domContentLoaded"] = vitals.DOMContentLoaded
+	}
+	if vitals.Load > 0 {
+		timings["load"] = vitals.Load
+	}
+
+	return timings
+}
+
+// formatMs formats a timing in milliseconds for output, leaving
+// unmeasured ones empty
+func formatMs(ms float64) string {
+	if ms <= 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("%.0f", ms)
+}
+
+// countPoorVitals counts each rated metric that isn't good as an issue
+func countPoorVitals(vitals webVitals) int {
+	issues := 0
+	for _, metric := range vitals.metrics() {
+		if metric.Rating == ratingNeedsImprovement || metric.Rating == ratingPoor {
+			issues++
+		}
+	}
+
+	return issues
+}
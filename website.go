@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"net/url"
+	"slices"
 	"strings"
 )
 
@@ -11,6 +12,7 @@ type Website struct {
 	originalURL string
 	scheme      string
 	domain      string
+	urls        []string // all input URLs on the domain, in input order
 }
 
 // NewWebsite creates a new Website instance
@@ -28,6 +30,7 @@ func NewWebsite(rawURL string) (*Website, error) {
 		domain:      strings.ToLower(parsed.Host),
 		scheme:      parsed.Scheme,
 		originalURL: rawURL,
+		urls:        []string{rawURL},
 	}, nil
 }
 
@@ -37,11 +40,36 @@ func (w *Website) isIgnored(ignoredPatterns []string) bool {
 	return isIgnoredResource(w.domain, ignoredPatterns)
 }
 
+// pageURLs returns the website's input URLs normalised for loading, with
+// their scheme replaced by the given one (if set)
+func (w *Website) pageURLs(scheme string) []string {
+	pageURLs := []string{}
+	for _, rawURL := range w.urls {
+		parsed, err := url.Parse(rawURL)
+		if err != nil {
+			continue // already validated when the website was created
+		}
+
+		if scheme != "" {
+			parsed.Scheme = scheme
+		}
+		parsed.Fragment = ""
+		if parsed.Path == "" {
+			parsed.Path = "/"
+		}
+
+		pageURLs = append(pageURLs, parsed.String())
+	}
+
+	return pageURLs
+}
+
 // filterWebsites converts raw URLs to websites and
-// filters out duplicates/ignored domains
+// filters out duplicates/ignored domains - further URLs on an
+// already seen domain are grouped under its website
 func FilterWebsites(rawURLs []string) []*Website {
 	websites := []*Website{}
-	seen := map[string]bool{}
+	seen := map[string]*Website{}
 
 	for _, url := range rawURLs {
 		if url == "" {
@@ -54,11 +82,18 @@ func FilterWebsites(rawURLs []string) []*Website {
 			continue
 		}
 
-		if seen[website.domain] || website.isIgnored(ignoredBusinessPatterns) {
+		if existing, ok := seen[website.domain]; ok {
+			if !slices.Contains(existing.urls, url) {
+				existing.urls = append(existing.urls, url)
+			}
+			continue
+		}
+
+		if website.isIgnored(ignoredBusinessPatterns) {
 			continue
 		}
 
-		seen[website.domain] = true
+		seen[website.domain] = website
 		websites = append(websites, website)
 	}
 
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/chromedp/cdproto/network"
+)
+
+// pages transferring more than this are flagged as heavy (Lighthouse starts
+// penalising total byte weight well below it)
+const heavyPageBytes = 3 * 1024 * 1024
+
+// resourceStats holds the number of requests of a group, and bytes they transferred
+type resourceStats struct {
+	Requests int   `json:"requests"`
+	Bytes    int64 `json:"bytes"`
+}
+
+// add counts a request in the group
+func (s *resourceStats) add(req networkRequest) {
+	s.Requests++
+	s.Bytes += req.transferred
+}
+
+// String formats the group's stats for output
+func (s resourceStats) String() string {
+	return fmt.Sprintf("%s (%d requests)", formatBytes(s.Bytes), s.Requests)
+}
+
+// resourceBreakdown groups requests by resource type
+type resourceBreakdown struct {
+	Document   resourceStats `json:"document"`
+	Script     resourceStats `json:"script"`
+	Stylesheet resourceStats `json:"stylesheet"`
+	Image      resourceStats `json:"image"`
+	Font       resourceStats `json:"font"`
+	Media      resourceStats `json:"media"`
+	XHR        resourceStats `json:"xhr"` // XHR and fetch requests
+	Other      resourceStats `json:"other"`
+}
+
+// group returns the stats of the given resource type's group
+func (b *resourceBreakdown) group(resourceType network.ResourceType) *resourceStats {
+	switch resourceType {
+	case network.ResourceTypeDocument:
+		return &b.Document
+	case network.ResourceTypeScript:
+		return &b.Script
+	case network.ResourceTypeStylesheet:
+		return &b.Stylesheet
+	case network.ResourceTypeImage:
+		return &b.Image
+	case network.ResourceTypeFont:
+		return &b.Font
+	case network.ResourceTypeMedia:
+		return &b.Media
+	case network.ResourceTypeXHR, network.ResourceTypeFetch:
+		return &b.XHR
+	default:
+		return &b.Other
+	}
+}
+
+// pageWeight holds the page's transferred bytes and requests, broken down
+// by resource type and by first/third party
+type pageWeight struct {
+	TotalBytes int64             `json:"totalBytes"`
+	Requests   int               `json:"requests"`
+	ByType     resourceBreakdown `json:"byType"`
+	FirstParty resourceStats     `json:"firstParty"`
+	ThirdParty resourceStats     `json:"thirdParty"`
+}
+
+// newPageWeight sums up requests made by a page on the site with the given domain
+func newPageWeight(domain string, requests []networkRequest) pageWeight {
+	weight := pageWeight{}
+	for _, req := range requests {
+		weight.TotalBytes += req.transferred
+		weight.Requests++
+		weight.ByType.group(req.resourceType).add(req)
+
+		if isFirstParty(domain, req.url) {
+			weight.FirstParty.add(req)
+		} else {
+			weight.ThirdParty.add(req)
+		}
+	}
+
+	return weight
+}
+
+// formatBytes formats a byte count in human readable units
+func formatBytes(bytes int64) string {
+	switch {
+	case bytes >= 1024*1024:
+		return fmt.Sprintf("%.1f MB", float64(bytes)/(1024*1024))
+	case bytes >= 1024:
+		return fmt.Sprintf("%.0f KB", float64(bytes)/1024)
+	default:
+		return fmt.Sprintf("%d B", bytes)
+	}
+}